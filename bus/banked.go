@@ -0,0 +1,109 @@
+package bus
+
+// Represents memory made of banks of the same
+// size, only one of which is mapped at a time.
+type Banked struct {
+	// Address the region is mapped at
+	base  uint16
+	banks [][]byte
+	// Index of the mapped bank
+	bank int
+}
+
+// Returns count banks of size bytes of zeroed RAM
+// mapped at base, with bank 0 mapped.
+func NewBanked(base uint16, size, count int) *Banked {
+	b := &Banked{base: base, banks: make([][]byte, count)}
+	for i := range b.banks {
+		b.banks[i] = make([]byte, size)
+	}
+	return b
+}
+
+// Maps bank n. Banks past the last
+// one wrap around to the first.
+func (b *Banked) SelectBank(n int) {
+	b.bank = n % len(b.banks)
+}
+
+// Returns the index of the mapped bank.
+func (b *Banked) Bank() int {
+	return b.bank
+}
+
+// Returns the byte at address a
+// of the mapped bank.
+func (b *Banked) GetByte(a uint16) byte {
+	return b.banks[b.bank][a-b.base]
+}
+
+// Sets v into address a of the mapped bank.
+func (b *Banked) SetByte(v byte, a uint16) {
+	b.banks[b.bank][a-b.base] = v
+}
+
+// Sets every byte of every bank, in order, to the
+// values returned by successive calls to next.
+func (b *Banked) Fill(next func() byte) {
+	for _, bank := range b.banks {
+		for i := range bank {
+			bank[i] = next()
+		}
+	}
+}
+
+// Represents work RAM. Bank 0 is always mapped at
+// 0xC000-0xCFFF, and one of the switchable banks
+// at 0xD000-0xDFFF.
+type WRAM struct {
+	bank0      *Memory
+	switchable *Banked
+}
+
+// Returns work RAM with the given number of switchable
+// banks: 1 on DMG models and 7 on CGB ones.
+func NewWRAM(banks int) *WRAM {
+	return &WRAM{
+		bank0:      NewMemory(WRAMStart, 0x1000),
+		switchable: NewBanked(WRAMStart+0x1000, 0x1000, banks),
+	}
+}
+
+// Maps bank n (1-7) at 0xD000-0xDFFF, as writing
+// into SVBK does. Bank 0 maps bank 1 instead.
+func (w *WRAM) SelectBank(n int) {
+	if n == 0 {
+		n = 1
+	}
+	w.switchable.SelectBank(n - 1)
+}
+
+// Returns the index of the bank mapped
+// at 0xD000-0xDFFF (1-7).
+func (w *WRAM) Bank() int {
+	return w.switchable.Bank() + 1
+}
+
+// Returns the byte at address a.
+func (w *WRAM) GetByte(a uint16) byte {
+	if a < WRAMStart+0x1000 {
+		return w.bank0.GetByte(a)
+	}
+	return w.switchable.GetByte(a)
+}
+
+// Sets v into address a.
+func (w *WRAM) SetByte(v byte, a uint16) {
+	if a < WRAMStart+0x1000 {
+		w.bank0.SetByte(v, a)
+		return
+	}
+	w.switchable.SetByte(v, a)
+}
+
+// Sets every byte of every bank, in order, to the
+// values returned by successive calls to next.
+func (w *WRAM) Fill(next func() byte) {
+	w.bank0.Fill(next)
+	w.switchable.Fill(next)
+}
@@ -0,0 +1,137 @@
+package bus
+
+import "github.com/markelmencia/gogb/model"
+
+/* INTERFACES */
+
+// Defines the memory the CPU sees through the address
+// bus. It is implemented both by Map, which dispatches
+// each access to the hardware mapped at its address, and
+// by the flat ram.RAM, which is handy for unit tests.
+type Bus interface {
+	// Returns the byte at address a.
+	GetByte(a uint16) byte
+	// Sets v into address a.
+	SetByte(v byte, a uint16)
+	// Returns the 16-bit value stored in a and a + 1.
+	Get16Bit(a uint16) uint16
+	// Sets the 16-bit value v into a and a + 1.
+	Set16Bit(v uint16, a uint16)
+}
+
+// Defines a piece of hardware mapped into a range
+// of the address space. Addresses are absolute, so
+// a region can tell which of its bytes is accessed
+// without knowing where it is mapped.
+type Region interface {
+	GetByte(a uint16) byte
+	SetByte(v byte, a uint16)
+}
+
+// Represents a region backed by memory that can be
+// filled directly, like it is at power-on. Every bank
+// is filled, whichever one is mapped.
+type Filler interface {
+	Fill(next func() byte)
+}
+
+/* MEMORY MAP */
+
+// Start address of each region of the address space.
+const (
+	CartridgeStart   uint16 = 0x0000
+	VRAMStart        uint16 = 0x8000
+	ExternalRAMStart uint16 = 0xA000
+	WRAMStart        uint16 = 0xC000
+	EchoStart        uint16 = 0xE000
+	OAMStart         uint16 = 0xFE00
+	UnusableStart    uint16 = 0xFEA0
+	IOStart          uint16 = 0xFF00
+	HRAMStart        uint16 = 0xFF80
+)
+
+// Represents the address space of the CPU, made of
+// the regions below. Every access is dispatched to
+// the region that covers its address.
+type Map struct {
+	Cartridge   Region // 0x0000-0x7FFF: Cartridge ROM
+	VRAM        Region // 0x8000-0x9FFF: Video RAM
+	ExternalRAM Region // 0xA000-0xBFFF: Cartridge RAM
+	WRAM        Region // 0xC000-0xDFFF: Work RAM
+	Echo        Region // 0xE000-0xFDFF: Echo RAM
+	OAM         Region // 0xFE00-0xFE9F: Object attribute memory
+	Unusable    Region // 0xFEA0-0xFEFF: Unusable area
+	IO          Region // 0xFF00-0xFF7F: I/O registers
+	HRAM        Region // 0xFF80-0xFFFF: High RAM and IE
+}
+
+// Returns the map of hardware of model md with the
+// cartridge ROM rom. Echo RAM mirrors 0xC000-0xDDFF
+// and every other region is backed by plain memory.
+//
+// VRAM and WRAM are banked, with the extra banks
+// of CGB models (see VBK and SVBK) if md is one.
+func NewMap(rom []byte, md model.Model) *Map {
+	vramBanks, wramBanks := 1, 1
+	if md.IsColor() {
+		vramBanks, wramBanks = 2, 7
+	}
+
+	m := &Map{
+		Cartridge:   NewROM(CartridgeStart, rom),
+		VRAM:        NewBanked(VRAMStart, 0x2000, vramBanks),
+		ExternalRAM: NewMemory(ExternalRAMStart, 0x2000),
+		WRAM:        NewWRAM(wramBanks),
+		OAM:         NewMemory(OAMStart, 0xA0),
+		Unusable:    NewUnusable(md),
+		IO:          NewMemory(IOStart, 0x80),
+		HRAM:        NewMemory(HRAMStart, 0x80),
+	}
+	m.Echo = Mirror{Map: m, Offset: EchoStart - WRAMStart}
+	return m
+}
+
+// Returns the region that covers address a.
+func (m *Map) region(a uint16) Region {
+	switch {
+	case a < VRAMStart:
+		return m.Cartridge
+	case a < ExternalRAMStart:
+		return m.VRAM
+	case a < WRAMStart:
+		return m.ExternalRAM
+	case a < EchoStart:
+		return m.WRAM
+	case a < OAMStart:
+		return m.Echo
+	case a < UnusableStart:
+		return m.OAM
+	case a < IOStart:
+		return m.Unusable
+	case a < HRAMStart:
+		return m.IO
+	}
+	return m.HRAM
+}
+
+// Returns the byte at address a.
+func (m *Map) GetByte(a uint16) byte {
+	return m.region(a).GetByte(a)
+}
+
+// Sets v into address a.
+func (m *Map) SetByte(v byte, a uint16) {
+	m.region(a).SetByte(v, a)
+}
+
+// Returns the 16-bit value stored in a
+// and a + 1
+func (m *Map) Get16Bit(a uint16) uint16 {
+	return uint16(m.GetByte(a+1))<<8 | uint16(m.GetByte(a))
+}
+
+// Sets the value v into a and a + 1
+func (m *Map) Set16Bit(v uint16, a uint16) {
+	m.SetByte(byte(v), a)
+	m.SetByte(byte(v>>8), a+1)
+}
@@ -0,0 +1,74 @@
+package bus
+
+// Represents a region backed by plain memory.
+type Memory struct {
+	// Address the region is mapped at
+	base uint16
+	data []byte
+	// True if writes are ignored
+	readOnly bool
+}
+
+// Returns a region of size bytes of
+// zeroed RAM mapped at base.
+func NewMemory(base uint16, size int) *Memory {
+	return &Memory{base: base, data: make([]byte, size)}
+}
+
+// Returns a read-only region mapped at base that
+// holds data. Reads past the end of data return 0xFF,
+// like an open bus.
+func NewROM(base uint16, data []byte) *Memory {
+	return &Memory{base: base, data: data, readOnly: true}
+}
+
+// Returns the byte at address a.
+func (m *Memory) GetByte(a uint16) byte {
+	i := int(a - m.base)
+	if i >= len(m.data) {
+		return 0xFF
+	}
+	return m.data[i]
+}
+
+// Sets v into address a, unless the
+// region is read-only.
+func (m *Memory) SetByte(v byte, a uint16) {
+	i := int(a - m.base)
+	if m.readOnly || i >= len(m.data) {
+		return
+	}
+	m.data[i] = v
+}
+
+// Sets every byte of the region to the values returned
+// by successive calls to next, unless it is read-only.
+func (m *Memory) Fill(next func() byte) {
+	if m.readOnly {
+		return
+	}
+	for i := range m.data {
+		m.data[i] = next()
+	}
+}
+
+// Represents a region that mirrors another part
+// of the address space, like echo RAM does
+// with WRAM.
+type Mirror struct {
+	// Address space the mirrored region is in
+	Map *Map
+	// Distance between the mirror and the
+	// mirrored region
+	Offset uint16
+}
+
+// Returns the byte at address a - Offset.
+func (m Mirror) GetByte(a uint16) byte {
+	return m.Map.GetByte(a - m.Offset)
+}
+
+// Sets v into address a - Offset.
+func (m Mirror) SetByte(v byte, a uint16) {
+	m.Map.SetByte(v, a-m.Offset)
+}
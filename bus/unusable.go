@@ -0,0 +1,49 @@
+package bus
+
+import "github.com/markelmencia/gogb/model"
+
+// Returns the region hardware of model m has mapped at
+// 0xFEA0-0xFEFF, which Nintendo prohibits using.
+//
+// Each model behaves differently:
+//   - DMG, MGB and SGB models read 0x00 and ignore writes.
+//   - CGB models hold 96 bytes of RAM, as revisions up to
+//     CPU CGB D do (their revision-specific masking of
+//     the values read is not emulated).
+//   - AGB models read the high nibble of the low byte of
+//     the address twice (eg. 0xFEAx reads 0xAA) and ignore
+//     writes.
+//
+// (read https://gbdev.io/pandocs/Memory_Map.html#fea0feff-range)
+// for more information.
+func NewUnusable(m model.Model) Region {
+	switch m {
+	case model.CGB:
+		return NewMemory(UnusableStart, 0x60)
+	case model.AGB:
+		return nibbleRegion{}
+	}
+	return zeroRegion{}
+}
+
+// Represents a region that reads 0x00
+// and ignores writes.
+type zeroRegion struct{}
+
+func (zeroRegion) GetByte(a uint16) byte {
+	return 0x00
+}
+
+func (zeroRegion) SetByte(v byte, a uint16) {}
+
+// Represents a region that reads the high nibble
+// of the low byte of the address twice and
+// ignores writes.
+type nibbleRegion struct{}
+
+func (nibbleRegion) GetByte(a uint16) byte {
+	n := byte(a) >> 4
+	return n<<4 | n
+}
+
+func (nibbleRegion) SetByte(v byte, a uint16) {}
@@ -0,0 +1,264 @@
+package cartridge
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Represents the cartridge header, at 0x0100-0x014F.
+// (read https://gbdev.io/pandocs/The_Cartridge_Header.html)
+// for more information.
+type Header struct {
+	// Instructions the boot ROM jumps to
+	EntryPoint [4]byte `json:"entryPoint"`
+	// True if the logo matches the one the
+	// DMG boot ROM expects
+	LogoMatches bool `json:"logoMatches"`
+	// Title in uppercase ASCII, without padding. Up to 16
+	// characters long on old cartridges, and up to 15 on
+	// CGB ones, or 11 if they have a manufacturer code.
+	Title string `json:"title"`
+	// 4 character code, only in some CGB cartridges
+	ManufacturerCode string `json:"manufacturerCode"`
+	CGBFlag          byte   `json:"cgbFlag"`
+	SGBFlag          byte   `json:"sgbFlag"`
+	// Mapper and hardware of the cartridge
+	Type     byte   `json:"type"`
+	TypeName string `json:"typeName"`
+	// ROM and RAM size codes, as stored in the header
+	ROMSizeCode byte `json:"romSizeCode"`
+	RAMSizeCode byte `json:"ramSizeCode"`
+	// ROM and RAM sizes in bytes. -1 if the size
+	// code is unknown.
+	ROMSize         int    `json:"romSize"`
+	RAMSize         int    `json:"ramSize"`
+	DestinationCode byte   `json:"destinationCode"`
+	OldLicenseeCode byte   `json:"oldLicenseeCode"`
+	NewLicenseeCode string `json:"newLicenseeCode"`
+	// Publisher the licensee codes refer to
+	Licensee string `json:"licensee"`
+	Version  byte   `json:"version"`
+	// Checksums stored in the header
+	HeaderChecksum byte   `json:"headerChecksum"`
+	GlobalChecksum uint16 `json:"globalChecksum"`
+	// Checksums computed from the cartridge data
+	ComputedHeaderChecksum byte   `json:"computedHeaderChecksum"`
+	ComputedGlobalChecksum uint16 `json:"computedGlobalChecksum"`
+}
+
+// Returns the header of the cartridge cart.
+func ParseHeader(cart []byte) (Header, error) {
+	// Checks cartridge size
+	if len(cart) < 0x150 {
+		return Header{}, fmt.Errorf("Cartridge is too small (%d bytes - Min. size: 336 bytes)",
+			len(cart),
+		)
+	}
+
+	h := Header{
+		LogoMatches:            bytes.Equal(cart[0x104:0x134], logoBitmap),
+		CGBFlag:                cart[0x143],
+		SGBFlag:                cart[0x146],
+		Type:                   cart[0x147],
+		TypeName:               romTypeToString[cart[0x147]],
+		ROMSizeCode:            cart[0x148],
+		RAMSizeCode:            cart[0x149],
+		ROMSize:                -1,
+		RAMSize:                -1,
+		DestinationCode:        cart[0x14A],
+		OldLicenseeCode:        cart[0x14B],
+		NewLicenseeCode:        string(cart[0x144:0x146]),
+		Version:                cart[0x14C],
+		HeaderChecksum:         cart[0x14D],
+		GlobalChecksum:         binary.BigEndian.Uint16(cart[0x14E:0x150]),
+		ComputedHeaderChecksum: GetCartHDChecksum(cart),
+		ComputedGlobalChecksum: GetCartGlobalChecksum(cart),
+	}
+	copy(h.EntryPoint[:], cart[0x100:0x104])
+
+	// On CGB cartridges the last byte of the title is the
+	// CGB flag, and the 4 before it may hold the
+	// manufacturer code instead
+	title := cart[0x134:0x144]
+	if h.CGBFlag&0x80 != 0 {
+		title = title[:15]
+		if code := cart[0x13F:0x143]; isManufacturerCode(code) {
+			title = title[:11]
+			h.ManufacturerCode = string(code)
+		}
+	}
+	if i := bytes.IndexByte(title, 0x00); i >= 0 {
+		title = title[:i]
+	}
+	h.Title = strings.TrimRight(string(title), " ")
+
+	if size, ok := GetRomSize(h.ROMSizeCode); ok {
+		h.ROMSize = int(size) * 1024
+	}
+	if size, ok := GetRamSize(h.RAMSizeCode); ok {
+		h.RAMSize = int(size) * 1024
+	}
+
+	// 0x33 means the new licensee code is used instead
+	h.Licensee = GetOldLicenseePublisher(h.OldLicenseeCode)
+	if h.OldLicenseeCode == 0x33 {
+		h.Licensee = GetNewLicenseePublisher(h.NewLicenseeCode)
+	}
+	return h, nil
+}
+
+// Returns true if code looks like a manufacturer
+// code: 4 uppercase letters or digits.
+func isManufacturerCode(code []byte) bool {
+	for _, c := range code {
+		if (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns true if the header checksum
+// matches the computed one.
+func (h Header) HeaderChecksumMatches() bool {
+	return h.HeaderChecksum == h.ComputedHeaderChecksum
+}
+
+// Returns true if the global checksum
+// matches the computed one.
+func (h Header) GlobalChecksumMatches() bool {
+	return h.GlobalChecksum == h.ComputedGlobalChecksum
+}
+
+// Prints the header fields into w in a human readable
+// form. The entry point instructions are disassembled
+// with dis, or printed as raw bytes if dis is nil.
+func (h Header) Print(w io.Writer, dis Disassembler) {
+	fmt.Fprintf(w, "Cartridge header data:\n\n")
+
+	// Title
+	fmt.Fprintf(w, "- ROM title: %s\n", h.Title)
+
+	// ROM version number
+	fmt.Fprintf(w, "- ROM version number: 0x%X\n", h.Version)
+
+	// Entry point instructions
+	fmt.Fprint(w, "- Entry point instructions: ")
+	if dis == nil {
+		fmt.Fprintf(w, "0x%X 0x%X 0x%X 0x%X\n",
+			h.EntryPoint[0], h.EntryPoint[1], h.EntryPoint[2], h.EntryPoint[3],
+		)
+	} else {
+		// The disassembly stops at the end of the entry
+		// point, usually after a jump to the actual code
+		var instructions []string
+		for i := 0; i < len(h.EntryPoint); {
+			in, n := dis(h.EntryPoint[i:])
+			instructions = append(instructions, in)
+			i += n
+		}
+		fmt.Fprintln(w, strings.Join(instructions, "; "))
+	}
+
+	// Logo dump check
+	matches := "Logo does not match expected dump"
+	if h.LogoMatches {
+		matches = "Logo matches expected dump"
+	}
+	fmt.Fprintf(w, "- ROM logo: %s\n", matches)
+
+	// Manufacturer code
+	manufacturer := h.ManufacturerCode
+	if manufacturer == "" {
+		manufacturer = "(None)"
+	}
+	fmt.Fprintf(w, "- Manufacturer code: %s\n", manufacturer)
+
+	// CGB flag
+	cgbFlagInfo := ""
+	switch h.CGBFlag {
+	case 0x00:
+		cgbFlagInfo = "0x00 (Old Cartridge)"
+	case 0x80:
+		cgbFlagInfo = "0x80 (GBC Enhanced)"
+	case 0xC0:
+		cgbFlagInfo = "0xC0 (GBC Only)"
+	default:
+		cgbFlagInfo = fmt.Sprintf("0x%X (Unknown value)", h.CGBFlag)
+	}
+	fmt.Fprintf(w, "- CGB flag value: %s\n", cgbFlagInfo)
+
+	// New licensee code
+	fmt.Fprintf(w, "- New licensee code: %s (%s)\n",
+		h.NewLicenseeCode, GetNewLicenseePublisher(h.NewLicenseeCode),
+	)
+
+	// SGB flag
+	sgbFlagInfo := ""
+	if h.SGBFlag == 0x03 {
+		sgbFlagInfo = "0x03 (supports SGB functions)"
+	} else {
+		sgbFlagInfo = fmt.Sprintf("0x%X (does not support SGB functions)", h.SGBFlag)
+	}
+	fmt.Fprintf(w, "- SGB Flag Value: %s\n", sgbFlagInfo)
+
+	// Cartridge type
+	fmt.Fprintf(w, "- Cartridge Type: 0x%X (%s)\n", h.Type, h.TypeName)
+
+	// ROM size
+	romSizeInfo := "Unknown size"
+	if h.ROMSize >= 0 {
+		romSizeInfo = fmt.Sprintf("%d KiB", h.ROMSize/1024)
+	}
+	fmt.Fprintf(w, "- ROM size code: 0x%X (%s)\n", h.ROMSizeCode, romSizeInfo)
+
+	// RAM size
+	ramSizeInfo := "Unknown size"
+	if h.RAMSize >= 0 {
+		ramSizeInfo = fmt.Sprintf("%d KiB", h.RAMSize/1024)
+	}
+	fmt.Fprintf(w, "- RAM size code: 0x%X (%s)\n", h.RAMSizeCode, ramSizeInfo)
+
+	// Destination code
+	destinationInfo := ""
+	switch h.DestinationCode {
+	case 0x00:
+		destinationInfo = "0x00 (Japan and possibly overseas)"
+	case 0x01:
+		destinationInfo = "0x01 (Overseas only)"
+	default:
+		destinationInfo = fmt.Sprintf("0x%X (Unknown code)", h.DestinationCode)
+	}
+	fmt.Fprintf(w, "- Destination code: %s\n", destinationInfo)
+
+	// Old licensee code
+	fmt.Fprintf(w, "- Old License code: 0x%X (%s)\n",
+		h.OldLicenseeCode, GetOldLicenseePublisher(h.OldLicenseeCode),
+	)
+
+	// Header checksum check
+	hdChecksumInfo := "Does not match actual checksum"
+	if h.HeaderChecksumMatches() {
+		hdChecksumInfo = "Matches actual checksum"
+	}
+	fmt.Fprintf(w, "- Header checksum: 0x%02X (%s)\n", h.HeaderChecksum, hdChecksumInfo)
+
+	// Global checksum check
+	globalChecksumInfo := "Does not match actual checksum"
+	if h.GlobalChecksumMatches() {
+		globalChecksumInfo = "Matches actual checksum"
+	}
+	fmt.Fprintf(w, "- Global checksum: 0x%04X (%s)\n", h.GlobalChecksum, globalChecksumInfo)
+}
+
+// Writes the header fields into w as indented JSON.
+func (h Header) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(h)
+}
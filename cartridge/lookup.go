@@ -271,7 +271,7 @@ var romTypeToString = map[byte]string{
 	0x13: "MBC3+RAM+BATTERY",
 	0x19: "MBC5",
 	0x1A: "MBC5+RAM",
-	0x1B: "MBC5`RAM`BATTERY",
+	0x1B: "MBC5+RAM+BATTERY",
 	0x1C: "MBC5+RUMBLE",
 	0x1D: "MBC5+RUMBLE+RAM",
 	0x1E: "MBC5+RUMBLE+RAM+BATTERY",
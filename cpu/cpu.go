@@ -95,43 +95,135 @@ type CPU struct {
 	HL uint16
 	// 16-bit registers
 	IR uint16 // Instruction Register
-	IE uint16 // Interrupt Enable
+	// Deprecated: IE is not a CPU register. It is mapped into
+	// memory at 0xFFFF, see package interrupts.
+	IE uint16
 	SP uint16 // Stack Pointer
 	PC uint16 // Program Counter
 
 	// True if interrupt handlers are activated
 	IME bool
+	// True if EI was executed and IME will be
+	// activated after the next instruction
+	IMEScheduled bool
+
+	// Power states
+	// True if the CPU is halted until an interrupt is pending
+	Halted bool
+	// True if the next opcode fetch will not increment PC
+	// (see HALT bug)
+	HaltBug bool
+	// True if the CPU is in low power mode until a joypad
+	// input line goes low
+	Stopped bool
+	// True if the CPU has locked up after fetching
+	// an illegal opcode
+	Locked bool
+	// M-cycles left until the CPU resumes after a
+	// CGB speed switch
+	SpeedSwitch int
 }
 
 /* GETTERS / SETTERS */
 
-// Gets the appropiate register getter
-// and returns the value of the register.
+// Returns the value of halve register h.
+//
+// If h is not a valid halve, 0 will be returned.
 func (c *CPU) GetHalve(h Halve) byte {
-	return halveToAccessor[h].Get(c)
+	switch h {
+	case A:
+		return byte(c.AF >> 8)
+	case F:
+		return byte(c.AF)
+	case B:
+		return byte(c.BC >> 8)
+	case C:
+		return byte(c.BC)
+	case D:
+		return byte(c.DE >> 8)
+	case E:
+		return byte(c.DE)
+	case H:
+		return byte(c.HL >> 8)
+	case L:
+		return byte(c.HL)
+	}
+	return 0
 }
 
-// Gets the appropiate register setter
-// and sets v into the register.
+// Sets v into halve register h.
+//
+// If h is not a valid halve, nothing is set.
 func (c *CPU) SetHalve(h Halve, v byte) {
-	halveToAccessor[h].Set(c, v)
+	switch h {
+	case A:
+		c.AF = setHigh(c.AF, v)
+	case F:
+		c.AF = setLow(c.AF, v)
+	case B:
+		c.BC = setHigh(c.BC, v)
+	case C:
+		c.BC = setLow(c.BC, v)
+	case D:
+		c.DE = setHigh(c.DE, v)
+	case E:
+		c.DE = setLow(c.DE, v)
+	case H:
+		c.HL = setHigh(c.HL, v)
+	case L:
+		c.HL = setLow(c.HL, v)
+	}
 }
 
-// Gets the appropiate register getter
-// and returns the value of the register.
+// Returns the value of register r.
+//
+// If r is not a valid register, 0 will be returned.
 func (c *CPU) GetReg(r Register) uint16 {
-	return registerToAccessor[r].Get(c)
+	if p := c.register(r); p != nil {
+		return *p
+	}
+	return 0
 }
 
-// Gets the appropiate register setter
-// and sets v into the register.
+// Sets v into register r.
+//
+// If r is not a valid register, nothing is set.
 func (c *CPU) SetReg(r Register, v uint16) {
-	registerToAccessor[r].Set(c, v)
+	if p := c.register(r); p != nil {
+		*p = v
+	}
+}
+
+// Returns a pointer to register r, or nil
+// if r is not a valid register.
+func (c *CPU) register(r Register) *uint16 {
+	switch r {
+	case AF:
+		return &c.AF
+	case BC:
+		return &c.BC
+	case DE:
+		return &c.DE
+	case HL:
+		return &c.HL
+	case IR:
+		return &c.IR
+	case IE:
+		return &c.IE
+	case SP:
+		return &c.SP
+	case PC:
+		return &c.PC
+	}
+	return nil
 }
 
 // Interface that stores both
 // the getter and the setter
 // of a halve.
+//
+// Deprecated: Registers are no longer accessed
+// through accessors, use GetHalve and SetHalve.
 type HalveAccessor struct {
 	Get func(*CPU) byte
 	Set func(*CPU, byte)
@@ -140,166 +232,27 @@ type HalveAccessor struct {
 // Interface that stores both
 // the getter and the setter
 // of a register.
+//
+// Deprecated: Registers are no longer accessed
+// through accessors, use GetReg and SetReg.
 type RegisterAccessor struct {
 	Get func(*CPU) uint16
 	Set func(*CPU, uint16)
 }
 
-// Getter note: In order to get the isolated value of a high 8-bit
-// register an AND operation must be performed to unset the lower
-// end of the 16-bit register via a mask (see HIGH_MASK and LOW_MASK).
-// Then, as the value is on the higher end, it must be shifted to the
-// lower end with a shift right operation in order to get the actual value.
-
-// Setter Note: To set an 8-bit register, it is important to unset the register
+// Setter note: To set an 8-bit register, it is important to unset the register
 // first, so the previous value does not overlap with the value to be set (v).
 // That is why before the set a masking AND operation is performed in the 16-bit register,
 // to only keep the halve of the register we do not want to change.
 
-// Map that contains a getter/setter pair
-// for each Halve register.
-var halveToAccessor = map[Halve]HalveAccessor{
-	A: {
-		Get: func(c *CPU) byte {
-			return byte(c.AF >> 8)
-		},
-		Set: func(c *CPU, v byte) {
-			c.AF = (c.AF & LOW_MASK) | (uint16(v) << 8)
-		},
-	},
-	F: {
-		Get: func(c *CPU) byte {
-			return byte(c.AF)
-		},
-		Set: func(c *CPU, v byte) {
-			v16 := uint16(v)
-			c.AF = c.AF & HIGH_MASK
-			c.AF |= v16
-		},
-	},
-	B: {
-		Get: func(c *CPU) byte {
-			return byte(c.BC >> 8)
-		},
-		Set: func(c *CPU, v byte) {
-			c.BC = (c.BC & LOW_MASK) | (uint16(v) << 8)
-		},
-	},
-	C: {
-		Get: func(c *CPU) byte {
-			return byte(c.BC)
-		},
-		Set: func(c *CPU, v byte) {
-			v16 := uint16(v)
-			c.BC = c.BC & HIGH_MASK
-			c.BC |= v16
-		},
-	},
-	D: {
-		Get: func(c *CPU) byte {
-			return byte(c.DE >> 8)
-		},
-		Set: func(c *CPU, v byte) {
-			c.DE = (c.DE & LOW_MASK) | (uint16(v) << 8)
-		},
-	},
-	E: {
-		Get: func(c *CPU) byte {
-			return byte(c.DE)
-		},
-		Set: func(c *CPU, v byte) {
-			v16 := uint16(v)
-			c.DE = c.DE & HIGH_MASK
-			c.DE |= v16
-		},
-	},
-	H: {
-		Get: func(c *CPU) byte {
-			return byte(c.HL >> 8)
-		},
-		Set: func(c *CPU, v byte) {
-			c.HL = (c.HL & LOW_MASK) | (uint16(v) << 8)
-		},
-	},
-	L: {
-		Get: func(c *CPU) byte {
-			return byte(c.HL)
-		},
-		Set: func(c *CPU, v byte) {
-			v16 := uint16(v)
-			c.HL = c.HL & HIGH_MASK
-			c.HL |= v16
-		},
-	},
+// Returns r with its high halve set to v.
+func setHigh(r uint16, v byte) uint16 {
+	return (r & LOW_MASK) | (uint16(v) << 8)
 }
 
-// Map that contains a getter/setter pair
-// for each register.
-var registerToAccessor = map[Register]RegisterAccessor{
-	AF: {
-		Get: func(c *CPU) uint16 {
-			return c.AF
-		},
-		Set: func(c *CPU, v uint16) {
-			c.AF = v
-		},
-	},
-	BC: {
-		Get: func(c *CPU) uint16 {
-			return c.BC
-		},
-		Set: func(c *CPU, v uint16) {
-			c.BC = v
-		},
-	},
-	DE: {
-		Get: func(c *CPU) uint16 {
-			return c.DE
-		},
-		Set: func(c *CPU, v uint16) {
-			c.DE = v
-		},
-	},
-	HL: {
-		Get: func(c *CPU) uint16 {
-			return c.HL
-		},
-		Set: func(c *CPU, v uint16) {
-			c.HL = v
-		},
-	},
-	IR: {
-		Get: func(c *CPU) uint16 {
-			return c.IR
-		},
-		Set: func(c *CPU, v uint16) {
-			c.IR = v
-		},
-	},
-	IE: {
-		Get: func(c *CPU) uint16 {
-			return c.IE
-		},
-		Set: func(c *CPU, v uint16) {
-			c.IE = v
-		},
-	},
-	SP: {
-		Get: func(c *CPU) uint16 {
-			return c.SP
-		},
-		Set: func(c *CPU, v uint16) {
-			c.SP = v
-		},
-	},
-	PC: {
-		Get: func(c *CPU) uint16 {
-			return c.PC
-		},
-		Set: func(c *CPU, v uint16) {
-			c.PC = v
-		},
-	},
+// Returns r with its low halve set to v.
+func setLow(r uint16, v byte) uint16 {
+	return (r & HIGH_MASK) | uint16(v)
 }
 
 /* MASKS */
@@ -310,30 +263,15 @@ const (
 	LOW_MASK  uint16 = 0x00FF
 )
 
-// Associates a bit index to
-// a mask to obtain only said
-// bit.
-var bitToMask = map[byte]byte{
-	0: 0b00000001,
-	1: 0b00000010,
-	2: 0b00000100,
-	3: 0b00001000,
-	4: 0b00010000,
-	5: 0b00100000,
-	6: 0b01000000,
-	7: 0b10000000,
-}
-
 // Returns a byte with a mask as its value.
 // This mask filters out everything but bit b.
 //
 // If b is not between 0-7, 0 will be returned.
 func GetBitMask(b byte) byte {
-	v, ok := bitToMask[b]
-	if !ok {
+	if b > 7 {
 		return 0
 	}
-	return v
+	return 1 << b
 }
 
 /* FLAGS */
@@ -341,7 +279,7 @@ func GetBitMask(b byte) byte {
 // Returns the appropiate mask
 // to obtain the corresponding
 // byte to the desired flag.
-var flagToMask = map[Flag]byte{
+var flagToMask = [4]byte{
 	FlagZ: 0b10000000,
 	FlagN: 0b01000000,
 	FlagH: 0b00100000,
@@ -352,33 +290,31 @@ var flagToMask = map[Flag]byte{
 // returns true if it was set. If it wasn't, it returns
 // false.
 func (c *CPU) IsFlag(f Flag) bool {
-	mask := flagToMask[f]
+	if int(f) >= len(flagToMask) {
+		return false
+	}
 
 	// We mask the value of F so that all the bits in the register
-	// are set to 0 except the bit that corresponds to the flag
-	masked := c.GetHalve(F) & mask
-
+	// are set to 0 except the bit that corresponds to the flag.
 	// If masked is 0, it means that the flag bit was also 0
-	if masked == 0 {
-		return false
-	} else { // If masked is non-0, it means that the flag bit was 1
-		return true
-	}
+	return byte(c.AF)&flagToMask[f] != 0
 }
 
 // Sets the desired flag f to 1 if s is true
 // or 0 if s is false.
 func (c *CPU) SetFlag(s bool, f Flag) {
-	mask := flagToMask[f]
-	var v byte
+	if int(f) >= len(flagToMask) {
+		return
+	}
+
+	mask := uint16(flagToMask[f])
 	if s {
 		// Sets the flag bit to 1 and leaves the rest untouched
-		v = c.GetHalve(F) | mask
+		c.AF |= mask
 	} else {
 		// Sets the flag bit to 0 and leaves the rest untouched
-		v = c.GetHalve(F) & ^mask
+		c.AF &^= mask
 	}
-	c.SetHalve(F, v)
 }
 
 // Prints the values of all registers
@@ -0,0 +1,215 @@
+package instructions
+
+import (
+	"github.com/markelmencia/gogb/cpu"
+	"github.com/markelmencia/gogb/emulator"
+)
+
+/* OPERAND ENCODINGS */
+
+// 8-bit operands in the order they are encoded in the
+// opcode bits. Index 6 stands for (HL), which has its own
+// handlers, so its value here is never used.
+var encodedHalves = [8]cpu.Halve{cpu.B, cpu.C, cpu.D, cpu.E, cpu.H, cpu.L, 0, cpu.A}
+
+// Index of (HL) among the encoded 8-bit operands.
+const encodedHL = 6
+
+// 16-bit operands of LD, INC, DEC and ADD in the
+// order they are encoded in the opcode bits.
+var encodedRegisters = [4]cpu.Register{cpu.BC, cpu.DE, cpu.HL, cpu.SP}
+
+// 16-bit operands of PUSH and POP in the order they
+// are encoded in the opcode bits.
+var encodedStackRegisters = [4]cpu.Register{cpu.BC, cpu.DE, cpu.HL, cpu.AF}
+
+// Conditions of JP, JR, CALL and RET in the order
+// they are encoded in the opcode bits.
+var encodedConditions = [4]cpu.CondType{cpu.CondNZ, cpu.CondZ, cpu.CondNC, cpu.CondC}
+
+/* DECODE TABLES */
+
+// Fills the decode tables of the emulator package with
+// every SM83 instruction, binding the operands that are
+// encoded in the opcode itself.
+func init() {
+	decodeBase(&emulator.Opcodes)
+	decodeCB(&emulator.CBOpcodes)
+
+	for op, o := range OpcodeTable {
+		emulator.OpcodeLengths[op] = o.Length
+		emulator.OpcodeBranches[op] = o.Branch
+	}
+}
+
+// Fills t with the base instruction set.
+func decodeBase(t *[256]emulator.Instruction) {
+	t[0x00] = NOP
+	t[0x10] = STOP
+	t[0x76] = HALT
+	t[0xF3] = DI
+	t[0xFB] = EI
+
+	// 0x00-0x3F: Miscellaneous loads, 16-bit arithmetic,
+	// INC/DEC, rotates and relative jumps
+	for i, rr := range encodedRegisters {
+		op := byte(i) << 4
+		t[op|0x01] = func(emu emulator.Emulation) int { return LDrrnn(rr, emu) }
+		t[op|0x03] = func(emu emulator.Emulation) int { return INCrr(rr, emu) }
+		t[op|0x09] = func(emu emulator.Emulation) int { return ADDHLrr(rr, emu) }
+		t[op|0x0B] = func(emu emulator.Emulation) int { return DECrr(rr, emu) }
+	}
+
+	for i, r := range encodedHalves {
+		op := byte(i) << 3
+		if i == encodedHL {
+			t[op|0x04] = INCHL
+			t[op|0x05] = DECHL
+			t[op|0x06] = LDHLn
+			continue
+		}
+		t[op|0x04] = func(emu emulator.Emulation) int { return INCr(r, emu) }
+		t[op|0x05] = func(emu emulator.Emulation) int { return DECr(r, emu) }
+		t[op|0x06] = func(emu emulator.Emulation) int { return LDra(r, emu) }
+	}
+
+	t[0x02] = LDBCa
+	t[0x12] = LDDEa
+	t[0x22] = LDHLap
+	t[0x32] = LDHLam
+	t[0x0A] = LDaBC
+	t[0x1A] = LDaDE
+	t[0x2A] = LDaHLp
+	t[0x3A] = LDaHLm
+
+	t[0x07] = RLCA
+	t[0x0F] = RRCA
+	t[0x17] = RLA
+	t[0x1F] = RRA
+	t[0x27] = DAA
+	t[0x2F] = CPL
+	t[0x37] = SCF
+	t[0x3F] = CCF
+
+	t[0x08] = LDnnSP
+	t[0x18] = JRe
+	for i, cc := range encodedConditions {
+		op := byte(i) << 3
+		t[0x20|op] = func(emu emulator.Emulation) int { return JRcce(cc, emu) }
+	}
+
+	// 0x40-0x7F: 8-bit loads between registers and (HL).
+	// 0x76 would be LD (HL), (HL), which is HALT instead
+	for i, dst := range encodedHalves {
+		for j, src := range encodedHalves {
+			op := 0x40 | byte(i)<<3 | byte(j)
+			switch {
+			case i == encodedHL && j == encodedHL:
+				continue
+			case i == encodedHL:
+				t[op] = func(emu emulator.Emulation) int { return LDHLr(src, emu) }
+			case j == encodedHL:
+				t[op] = func(emu emulator.Emulation) int { return LDrHL(dst, emu) }
+			default:
+				t[op] = func(emu emulator.Emulation) int { return LDrr(dst, src, emu) }
+			}
+		}
+	}
+
+	// 0x80-0xBF: 8-bit arithmetic and logic with A
+	aluRegister := [8]func(cpu.Halve, emulator.Emulation) int{
+		ADDr, ADCr, SUBr, SBCr, ANDr, XORr, ORr, CPr,
+	}
+	aluHL := [8]emulator.Instruction{
+		ADDHL, ADCHL, SUBHL, SBCHL, ANDHL, XORHL, ORHL, CPHL,
+	}
+	aluImmediate := [8]emulator.Instruction{
+		ADDn, ADCn, SUBn, SBCn, ANDn, XORn, ORn, CPn,
+	}
+	for i, alu := range aluRegister {
+		for j, r := range encodedHalves {
+			op := 0x80 | byte(i)<<3 | byte(j)
+			if j == encodedHL {
+				t[op] = aluHL[i]
+				continue
+			}
+			t[op] = func(emu emulator.Emulation) int { return alu(r, emu) }
+		}
+		t[0xC6|byte(i)<<3] = aluImmediate[i]
+	}
+
+	// 0xC0-0xFF: Control flow, stack and high memory
+	for i, cc := range encodedConditions {
+		op := byte(i) << 3
+		t[0xC0|op] = func(emu emulator.Emulation) int { return RETcc(cc, emu) }
+		t[0xC2|op] = func(emu emulator.Emulation) int { return JPccnn(cc, emu) }
+		t[0xC4|op] = func(emu emulator.Emulation) int { return CALLccnn(cc, emu) }
+	}
+
+	for i, rr := range encodedStackRegisters {
+		op := byte(i) << 4
+		t[0xC1|op] = func(emu emulator.Emulation) int { return POPrr(rr, emu) }
+		t[0xC5|op] = func(emu emulator.Emulation) int { return PUSHrr(rr, emu) }
+	}
+
+	for i := range 8 {
+		n := byte(i) << 3
+		t[0xC7|n] = func(emu emulator.Emulation) int { return RSTn(n, emu) }
+	}
+
+	t[0xC3] = JPnn
+	t[0xC9] = RET
+	t[0xCD] = CALLnn
+	t[0xD9] = RETI
+	t[0xE9] = JPHL
+
+	t[0xE0] = LDHnA
+	t[0xF0] = LDHAn
+	t[0xE2] = LDHCa
+	t[0xF2] = LDHaC
+	t[0xEA] = LDnnA
+	t[0xFA] = LDAnn
+
+	t[0xE8] = ADDSPpe
+	t[0xF8] = LDHLSPpe
+	t[0xF9] = LDSPHL
+}
+
+// Fills t with the instructions prefixed by 0xCB.
+func decodeCB(t *[256]emulator.Instruction) {
+	// 0x00-0x3F: Rotates, shifts and SWAP
+	shiftRegister := [8]func(cpu.Halve, emulator.Emulation) int{
+		RLCr, RRCr, RLr, RRr, SLAr, SRAr, SWAPr, SRLr,
+	}
+	shiftHL := [8]emulator.Instruction{
+		RLCHL, RRCHL, RLHL, RRHL, SLAHL, SRAHL, SWAPHL, SRLHL,
+	}
+	for i, shift := range shiftRegister {
+		for j, r := range encodedHalves {
+			op := byte(i)<<3 | byte(j)
+			if j == encodedHL {
+				t[op] = shiftHL[i]
+				continue
+			}
+			t[op] = func(emu emulator.Emulation) int { return shift(r, emu) }
+		}
+	}
+
+	// 0x40-0xFF: BIT, RES and SET, with the bit
+	// index encoded in bits 3-5 of the opcode
+	bitRegister := [3]func(byte, cpu.Halve, emulator.Emulation) int{BITbr, RESbr, SETbr}
+	bitHL := [3]func(byte, emulator.Emulation) int{BITbHL, RESbHL, SETbHL}
+	for i, bitOp := range bitRegister {
+		for b := range byte(8) {
+			for j, r := range encodedHalves {
+				op := byte(i+1)<<6 | b<<3 | byte(j)
+				if j == encodedHL {
+					hlOp := bitHL[i]
+					t[op] = func(emu emulator.Emulation) int { return hlOp(b, emu) }
+					continue
+				}
+				t[op] = func(emu emulator.Emulation) int { return bitOp(b, r, emu) }
+			}
+		}
+	}
+}
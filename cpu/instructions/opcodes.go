@@ -0,0 +1,362 @@
+package instructions
+
+import (
+	"fmt"
+	"strings"
+)
+
+/* TYPE DEFINITIONS */
+
+// Defines how the operand that follows an
+// opcode is encoded.
+type Operand byte
+
+// Defines how an instruction affects a flag.
+type FlagEffect byte
+
+// Describes a single opcode.
+type Opcode struct {
+	// Mnemonic with the operand, if any, written as
+	// n8, n16 or e8 (eg. "LD B, n8")
+	Mnemonic string
+	Operand  Operand
+	// Length in bytes, including the opcode
+	// and, for CB instructions, the prefix
+	Length int
+	// M-cycles taken. For conditional instructions,
+	// the cycles taken when the condition is met
+	Cycles int
+	// M-cycles taken when the condition is not met.
+	// Equal to Cycles for unconditional instructions
+	CyclesNotTaken int
+	// Effect on each flag, indexed by cpu.Flag
+	Flags [4]FlagEffect
+	// True if the instruction may write PC
+	// (jumps, calls, returns and restarts)
+	Branch bool
+}
+
+/* ENUM DEFINITIONS */
+
+// Defines an enum with each operand encoding.
+const (
+	// No operand
+	OperandNone Operand = iota
+	// 8-bit immediate value
+	OperandN8
+	// 16-bit little endian immediate value
+	OperandN16
+	// 8-bit signed offset
+	OperandE8
+)
+
+// Defines an enum with each flag effect.
+const (
+	// The flag is left untouched
+	FlagPreserved FlagEffect = iota
+	// The flag is always set to 0
+	FlagReset
+	// The flag is always set to 1
+	FlagSet
+	// The flag depends on the result
+	FlagComputed
+)
+
+// Returns the number of bytes taken by the operand.
+func (o Operand) Length() int {
+	switch o {
+	case OperandN8, OperandE8:
+		return 1
+	case OperandN16:
+		return 2
+	}
+	return 0
+}
+
+// Returns true if the opcode is a valid instruction.
+// The 11 illegal opcodes describe no instruction.
+func (o Opcode) Valid() bool {
+	return o.Length != 0
+}
+
+// Returns true if the cycles taken by the
+// instruction depend on a condition.
+func (o Opcode) Conditional() bool {
+	return o.Cycles != o.CyclesNotTaken
+}
+
+/* OPCODE TABLES */
+
+// Describes every instruction in the base
+// instruction set, indexed by opcode. 0xCB
+// describes the prefix alone.
+var OpcodeTable = baseOpcodeTable()
+
+// Describes every instruction prefixed by
+// 0xCB, indexed by the second opcode.
+var CBOpcodeTable = cbOpcodeTable()
+
+// Names of the encoded 8-bit operands.
+var halveNames = [8]string{"B", "C", "D", "E", "H", "L", "(HL)", "A"}
+
+// Names of the encoded 16-bit operands.
+var registerNames = [4]string{"BC", "DE", "HL", "SP"}
+
+// Names of the encoded 16-bit stack operands.
+var stackRegisterNames = [4]string{"BC", "DE", "HL", "AF"}
+
+// Names of the encoded conditions.
+var conditionNames = [4]string{"NZ", "Z", "NC", "C"}
+
+// Returns an opcode description. flags is written
+// as in the usual opcode tables, one character per
+// flag in ZNHC order: "-" if the flag is preserved,
+// "0" if it is reset, "1" if it is set and its own
+// letter if it is computed (eg. "Z0H-").
+func opcode(mnemonic string, cycles, notTaken int, flags string) Opcode {
+	o := Opcode{
+		Mnemonic:       mnemonic,
+		Cycles:         cycles,
+		CyclesNotTaken: notTaken,
+	}
+
+	switch {
+	case strings.Contains(mnemonic, "n16"):
+		o.Operand = OperandN16
+	case strings.Contains(mnemonic, "n8"):
+		o.Operand = OperandN8
+	case strings.Contains(mnemonic, "e8"):
+		o.Operand = OperandE8
+	}
+	o.Length = 1 + o.Operand.Length()
+
+	for _, b := range []string{"JP", "JR", "CALL", "RET", "RST"} {
+		if strings.HasPrefix(mnemonic, b) {
+			o.Branch = true
+		}
+	}
+
+	for i, c := range flags {
+		switch c {
+		case '-':
+			o.Flags[i] = FlagPreserved
+		case '0':
+			o.Flags[i] = FlagReset
+		case '1':
+			o.Flags[i] = FlagSet
+		default:
+			o.Flags[i] = FlagComputed
+		}
+	}
+	return o
+}
+
+// Returns the description of the base instruction set.
+// Mirrors the structure of decodeBase.
+func baseOpcodeTable() [256]Opcode {
+	var t [256]Opcode
+	t[0x00] = opcode("NOP", 1, 1, "----")
+	t[0x10] = opcode("STOP n8", 1, 1, "----")
+	t[0x76] = opcode("HALT", 1, 1, "----")
+	t[0xCB] = opcode("PREFIX CB", 1, 1, "----")
+	t[0xF3] = opcode("DI", 1, 1, "----")
+	t[0xFB] = opcode("EI", 1, 1, "----")
+
+	// 0x00-0x3F: Miscellaneous loads, 16-bit arithmetic,
+	// INC/DEC, rotates and relative jumps
+	for i, rr := range registerNames {
+		op := byte(i) << 4
+		t[op|0x01] = opcode("LD "+rr+", n16", 3, 3, "----")
+		t[op|0x03] = opcode("INC "+rr, 2, 2, "----")
+		t[op|0x09] = opcode("ADD HL, "+rr, 2, 2, "-0HC")
+		t[op|0x0B] = opcode("DEC "+rr, 2, 2, "----")
+	}
+
+	for i, r := range halveNames {
+		op := byte(i) << 3
+		if i == encodedHL {
+			t[op|0x04] = opcode("INC (HL)", 3, 3, "Z0H-")
+			t[op|0x05] = opcode("DEC (HL)", 3, 3, "Z1H-")
+			t[op|0x06] = opcode("LD (HL), n8", 3, 3, "----")
+			continue
+		}
+		t[op|0x04] = opcode("INC "+r, 1, 1, "Z0H-")
+		t[op|0x05] = opcode("DEC "+r, 1, 1, "Z1H-")
+		t[op|0x06] = opcode("LD "+r+", n8", 2, 2, "----")
+	}
+
+	t[0x02] = opcode("LD (BC), A", 2, 2, "----")
+	t[0x12] = opcode("LD (DE), A", 2, 2, "----")
+	t[0x22] = opcode("LD (HL+), A", 2, 2, "----")
+	t[0x32] = opcode("LD (HL-), A", 2, 2, "----")
+	t[0x0A] = opcode("LD A, (BC)", 2, 2, "----")
+	t[0x1A] = opcode("LD A, (DE)", 2, 2, "----")
+	t[0x2A] = opcode("LD A, (HL+)", 2, 2, "----")
+	t[0x3A] = opcode("LD A, (HL-)", 2, 2, "----")
+
+	t[0x07] = opcode("RLCA", 1, 1, "000C")
+	t[0x0F] = opcode("RRCA", 1, 1, "000C")
+	t[0x17] = opcode("RLA", 1, 1, "000C")
+	t[0x1F] = opcode("RRA", 1, 1, "000C")
+	t[0x27] = opcode("DAA", 1, 1, "Z-0C")
+	t[0x2F] = opcode("CPL", 1, 1, "-11-")
+	t[0x37] = opcode("SCF", 1, 1, "-001")
+	t[0x3F] = opcode("CCF", 1, 1, "-00C")
+
+	t[0x08] = opcode("LD (n16), SP", 5, 5, "----")
+	t[0x18] = opcode("JR e8", 3, 3, "----")
+	for i, cc := range conditionNames {
+		op := byte(i) << 3
+		t[0x20|op] = opcode("JR "+cc+", e8", 3, 2, "----")
+	}
+
+	// 0x40-0x7F: 8-bit loads between registers and (HL).
+	// 0x76 would be LD (HL), (HL), which is HALT instead
+	for i, dst := range halveNames {
+		for j, src := range halveNames {
+			op := 0x40 | byte(i)<<3 | byte(j)
+			switch {
+			case i == encodedHL && j == encodedHL:
+				continue
+			case i == encodedHL || j == encodedHL:
+				t[op] = opcode("LD "+dst+", "+src, 2, 2, "----")
+			default:
+				t[op] = opcode("LD "+dst+", "+src, 1, 1, "----")
+			}
+		}
+	}
+
+	// 0x80-0xBF: 8-bit arithmetic and logic with A
+	aluNames := [8]string{"ADD A, ", "ADC A, ", "SUB A, ", "SBC A, ", "AND A, ", "XOR A, ", "OR A, ", "CP A, "}
+	aluFlags := [8]string{"Z0HC", "Z0HC", "Z1HC", "Z1HC", "Z010", "Z000", "Z000", "Z1HC"}
+	for i, alu := range aluNames {
+		for j, r := range halveNames {
+			op := 0x80 | byte(i)<<3 | byte(j)
+			if j == encodedHL {
+				t[op] = opcode(alu+r, 2, 2, aluFlags[i])
+				continue
+			}
+			t[op] = opcode(alu+r, 1, 1, aluFlags[i])
+		}
+		t[0xC6|byte(i)<<3] = opcode(alu+"n8", 2, 2, aluFlags[i])
+	}
+
+	// 0xC0-0xFF: Control flow, stack and high memory
+	for i, cc := range conditionNames {
+		op := byte(i) << 3
+		t[0xC0|op] = opcode("RET "+cc, 5, 2, "----")
+		t[0xC2|op] = opcode("JP "+cc+", n16", 4, 3, "----")
+		t[0xC4|op] = opcode("CALL "+cc+", n16", 6, 3, "----")
+	}
+
+	for i, rr := range stackRegisterNames {
+		op := byte(i) << 4
+		t[0xC1|op] = opcode("POP "+rr, 3, 3, "----")
+		t[0xC5|op] = opcode("PUSH "+rr, 4, 4, "----")
+	}
+	// POP AF loads every flag from the stack
+	t[0xF1] = opcode("POP AF", 3, 3, "ZNHC")
+
+	for i := range 8 {
+		n := byte(i) << 3
+		t[0xC7|n] = opcode(fmt.Sprintf("RST $%02X", n), 4, 4, "----")
+	}
+
+	t[0xC3] = opcode("JP n16", 4, 4, "----")
+	t[0xC9] = opcode("RET", 4, 4, "----")
+	t[0xCD] = opcode("CALL n16", 6, 6, "----")
+	t[0xD9] = opcode("RETI", 4, 4, "----")
+	t[0xE9] = opcode("JP HL", 1, 1, "----")
+
+	t[0xE0] = opcode("LDH (n8), A", 3, 3, "----")
+	t[0xF0] = opcode("LDH A, (n8)", 3, 3, "----")
+	t[0xE2] = opcode("LDH (C), A", 2, 2, "----")
+	t[0xF2] = opcode("LDH A, (C)", 2, 2, "----")
+	t[0xEA] = opcode("LD (n16), A", 4, 4, "----")
+	t[0xFA] = opcode("LD A, (n16)", 4, 4, "----")
+
+	t[0xE8] = opcode("ADD SP, e8", 4, 4, "00HC")
+	t[0xF8] = opcode("LD HL, SP+e8", 3, 3, "00HC")
+	t[0xF9] = opcode("LD SP, HL", 2, 2, "----")
+	return t
+}
+
+// Returns the description of the instructions prefixed
+// by 0xCB. Mirrors the structure of decodeCB.
+func cbOpcodeTable() [256]Opcode {
+	var t [256]Opcode
+
+	// 0x00-0x3F: Rotates, shifts and SWAP
+	shiftNames := [8]string{"RLC ", "RRC ", "RL ", "RR ", "SLA ", "SRA ", "SWAP ", "SRL "}
+	shiftFlags := [8]string{"Z00C", "Z00C", "Z00C", "Z00C", "Z00C", "Z00C", "Z000", "Z00C"}
+	for i, shift := range shiftNames {
+		for j, r := range halveNames {
+			op := byte(i)<<3 | byte(j)
+			if j == encodedHL {
+				t[op] = opcode(shift+r, 4, 4, shiftFlags[i])
+			} else {
+				t[op] = opcode(shift+r, 2, 2, shiftFlags[i])
+			}
+		}
+	}
+
+	// 0x40-0xFF: BIT, RES and SET, with the bit
+	// index encoded in bits 3-5 of the opcode
+	bitNames := [3]string{"BIT ", "RES ", "SET "}
+	bitFlags := [3]string{"Z01-", "----", "----"}
+	bitHLCycles := [3]int{3, 4, 4}
+	for i, bitOp := range bitNames {
+		for b := range byte(8) {
+			for j, r := range halveNames {
+				op := byte(i+1)<<6 | b<<3 | byte(j)
+				mnemonic := fmt.Sprintf("%s%d, %s", bitOp, b, r)
+				if j == encodedHL {
+					t[op] = opcode(mnemonic, bitHLCycles[i], bitHLCycles[i], bitFlags[i])
+				} else {
+					t[op] = opcode(mnemonic, 2, 2, bitFlags[i])
+				}
+			}
+		}
+	}
+
+	// Every CB instruction is one byte longer
+	// because of the prefix
+	for i := range t {
+		t[i].Length++
+	}
+	return t
+}
+
+/* DISASSEMBLY */
+
+// Returns the disassembled instruction at the start
+// of code and its length in bytes. Illegal opcodes and
+// instructions cut short by the end of code are
+// returned as raw data bytes.
+func Disassemble(code []byte) (string, int) {
+	if len(code) == 0 {
+		return "", 0
+	}
+
+	o := OpcodeTable[code[0]]
+	if code[0] == 0xCB && len(code) > 1 {
+		o = CBOpcodeTable[code[1]]
+	}
+
+	if !o.Valid() || len(code) < o.Length {
+		return fmt.Sprintf("DB $%02X", code[0]), 1
+	}
+
+	operand := code[o.Length-o.Operand.Length() : o.Length]
+	switch o.Operand {
+	case OperandN8:
+		return strings.Replace(o.Mnemonic, "n8", fmt.Sprintf("$%02X", operand[0]), 1), o.Length
+	case OperandN16:
+		nn := uint16(operand[1])<<8 | uint16(operand[0])
+		return strings.Replace(o.Mnemonic, "n16", fmt.Sprintf("$%04X", nn), 1), o.Length
+	case OperandE8:
+		e := fmt.Sprintf("%+d", int8(operand[0]))
+		return strings.Replace(strings.Replace(o.Mnemonic, "+e8", e, 1), "e8", e, 1), o.Length
+	}
+	return o.Mnemonic, o.Length
+}
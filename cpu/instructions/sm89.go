@@ -111,102 +111,133 @@ func sub16(a, b uint16) (uint16, bool, bool) {
 	return result, carry, halfCarry
 }
 
+// Pushes v into the stack: SP is decremented and the
+// high byte is written, on its own M-cycle, and then
+// the same happens with the low byte. SP is left
+// pointing to the low byte.
+func push16(v uint16, emu emulator.Emulation) {
+	emu.CPU.SP--
+	emu.Write(byte(v>>8), emu.CPU.SP)
+	emu.CPU.SP--
+	emu.Write(byte(v), emu.CPU.SP)
+}
+
+// Pops a value from the stack: the low byte is read
+// at SP and the high byte at SP + 1, on an M-cycle
+// each, and SP is incremented past both.
+func pop16(emu emulator.Emulation) uint16 {
+	lo := emu.Read(emu.CPU.SP)
+	emu.CPU.SP++
+	hi := emu.Read(emu.CPU.SP)
+	emu.CPU.SP++
+	return uint16(hi)<<8 | uint16(lo)
+}
+
 /* SM89 INSTRUCTIONS */
 
 // LD r, r': Load register (register) (8-Bit)
 //
 // Loads the value of r' into r.
-func LDrr(dst, src cpu.Halve, emu emulator.Emulation) {
+func LDrr(dst, src cpu.Halve, emu emulator.Emulation) int {
 	v := emu.CPU.GetHalve(src)
 	emu.CPU.SetHalve(dst, v)
 	emu.CPU.PC++
+	return 1
 }
 
 // LD r, n: Load register (immediate)
 //
 // Loads n (the value in memory next to the instruction)
 // into register r.
-func LDra(dst cpu.Halve, emu emulator.Emulation) {
+func LDra(dst cpu.Halve, emu emulator.Emulation) int {
 	emu.CPU.PC++
-	v := emu.RAM.GetByte(emu.CPU.PC)
+	v := emu.Read(emu.CPU.PC)
 	emu.CPU.SetHalve(dst, v)
 	emu.CPU.PC++
+	return 2
 }
 
 // LD r, (HL): Load register (indirect HL)
 //
 // Loads the memory value in the index inside register
 // HL (16 bits) into r.
-func LDrHL(dst cpu.Halve, emu emulator.Emulation) {
+func LDrHL(dst cpu.Halve, emu emulator.Emulation) int {
 	a := emu.CPU.HL
-	v := emu.RAM.GetByte(a)
+	v := emu.Read(a)
 	emu.CPU.SetHalve(dst, v)
 	emu.CPU.PC++
+	return 2
 }
 
 // LD (HL), r: Load from register (indirect HL)
 //
 // Writes the value in register r into the memory
 // address specified in HL.
-func LDHLr(src cpu.Halve, emu emulator.Emulation) {
+func LDHLr(src cpu.Halve, emu emulator.Emulation) int {
 	a := emu.CPU.HL
 	v := emu.CPU.GetHalve(src)
-	emu.RAM.SetByte(v, a)
+	emu.Write(v, a)
 	emu.CPU.PC++
+	return 2
 }
 
 // LD (HL), n: Load from immediate data (indirect HL)
 //
 // Writes the value of the memory address next to
 // the instruction into the memory address specified in HL.
-func LDHLn(emu emulator.Emulation) {
+func LDHLn(emu emulator.Emulation) int {
 	a := emu.CPU.HL
 	emu.CPU.PC++
-	v := emu.RAM.GetByte(emu.CPU.PC)
-	emu.RAM.SetByte(v, a)
+	v := emu.Read(emu.CPU.PC)
+	emu.Write(v, a)
 	emu.CPU.PC++
+	return 3
 }
 
 // LD A, (BC): Load accumulator (indirect BC)
 //
 // Loads the memory value specified in BC into A.
-func LDaBC(emu emulator.Emulation) {
+func LDaBC(emu emulator.Emulation) int {
 	a := emu.CPU.BC
-	v := emu.RAM.GetByte(a)
+	v := emu.Read(a)
 	emu.CPU.SetHalve(cpu.A, v)
 	emu.CPU.PC++
+	return 2
 }
 
 // LD A, (DE): Load accumulator (indirect DE)
 //
 // Loads the memory value specified in DE into A.
-func LDaDE(emu emulator.Emulation) {
+func LDaDE(emu emulator.Emulation) int {
 	a := emu.CPU.DE
-	v := emu.RAM.GetByte(a)
+	v := emu.Read(a)
 	emu.CPU.SetHalve(cpu.A, v)
 	emu.CPU.PC++
+	return 2
 }
 
 // LD (BC), A: Load accumulator (indirect BC)
 //
 // Writes the value of register A into the
 // address specified in BC.
-func LDBCa(emu emulator.Emulation) {
+func LDBCa(emu emulator.Emulation) int {
 	a := emu.CPU.BC
 	v := emu.CPU.GetHalve(cpu.A)
-	emu.RAM.SetByte(v, a)
+	emu.Write(v, a)
 	emu.CPU.PC++
+	return 2
 }
 
 // LD (DE), A: Load accumulator (indirect DE)
 //
 // Writes the value of register A into the
 // address specified in BC.
-func LDDEa(emu emulator.Emulation) {
+func LDDEa(emu emulator.Emulation) int {
 	a := emu.CPU.DE
 	v := emu.CPU.GetHalve(cpu.A)
-	emu.RAM.SetByte(v, a)
+	emu.Write(v, a)
 	emu.CPU.PC++
+	return 2
 }
 
 // LD A, (nn): Load accumulator (direct)
@@ -214,17 +245,18 @@ func LDDEa(emu emulator.Emulation) {
 // Loads into A the memory data of the
 // address obtained from the next two
 // RAM values of the instruction.
-func LDAnn(emu emulator.Emulation) {
+func LDAnn(emu emulator.Emulation) int {
 	emu.CPU.PC++
-	nLo := emu.RAM.GetByte(emu.CPU.PC)
+	nLo := emu.Read(emu.CPU.PC)
 	emu.CPU.PC++
-	nHi := emu.RAM.GetByte(emu.CPU.PC)
+	nHi := emu.Read(emu.CPU.PC)
 
 	a := uint16(nHi)<<8 | uint16(nLo)
-	v := emu.RAM.GetByte(a)
+	v := emu.Read(a)
 
 	emu.CPU.SetHalve(cpu.A, v)
 	emu.CPU.PC++
+	return 4
 }
 
 // LD (nn), A: Load from accumulator (direct)
@@ -232,182 +264,196 @@ func LDAnn(emu emulator.Emulation) {
 // Writes into the memory address
 // specified by the next two RAM bytes
 // of the instruction the value of A.
-func LDnnA(emu emulator.Emulation) {
+func LDnnA(emu emulator.Emulation) int {
 	emu.CPU.PC++
-	nLo := emu.RAM.GetByte(emu.CPU.PC)
+	nLo := emu.Read(emu.CPU.PC)
 	emu.CPU.PC++
-	nHi := emu.RAM.GetByte(emu.CPU.PC)
+	nHi := emu.Read(emu.CPU.PC)
 
 	a := uint16(nHi)<<8 | uint16(nLo)
 	v := emu.CPU.GetHalve(cpu.A)
 
-	emu.RAM.SetByte(v, a)
+	emu.Write(v, a)
 	emu.CPU.PC++
+	return 4
 }
 
 // LDH A, (C): Load accumulator (indirect 0xFF00+C)
 //
 // Loads the value in memory of the address 0xFF00 + C
 // into A.
-func LDHaC(emu emulator.Emulation) {
+func LDHaC(emu emulator.Emulation) int {
 	a := 0xFF00 | uint16(emu.CPU.GetHalve(cpu.C))
-	v := emu.RAM.GetByte(a)
+	v := emu.Read(a)
 	emu.CPU.SetHalve(cpu.A, v)
 	emu.CPU.PC++
+	return 2
 }
 
 // LDH (C), A: Load from accumulator (indirect 0xFF00+C)
 //
 // Loads the value of A into the memory address 0xFF00 + C.
-func LDHCa(emu emulator.Emulation) {
+func LDHCa(emu emulator.Emulation) int {
 	a := 0xFF00 | uint16(emu.CPU.GetHalve(cpu.C))
 	v := emu.CPU.GetHalve(cpu.A)
-	emu.RAM.SetByte(v, a)
+	emu.Write(v, a)
 	emu.CPU.PC++
+	return 2
 }
 
 // LDH A, (n): Load accumulator (indirect 0xFF00+n)
 //
 // Loads the value memory in 0xFF00 + n (next value
 // in memory from the instruction) into A.
-func LDHAn(emu emulator.Emulation) {
+func LDHAn(emu emulator.Emulation) int {
 	emu.CPU.PC++
-	a := 0xFF00 | uint16(emu.RAM.GetByte(emu.CPU.PC))
-	v := emu.RAM.GetByte(a)
+	a := 0xFF00 | uint16(emu.Read(emu.CPU.PC))
+	v := emu.Read(a)
 	emu.CPU.SetHalve(cpu.A, v)
 	emu.CPU.PC++
+	return 3
 }
 
 // LDH (n), A: Load from accumulator (indirect 0xFF00+n)
 //
 // Loads the value of A into the memory address 0xFF + n.
-func LDHnA(emu emulator.Emulation) {
+func LDHnA(emu emulator.Emulation) int {
 	emu.CPU.PC++
-	a := 0xFF00 | uint16(emu.RAM.GetByte(emu.CPU.PC))
+	a := 0xFF00 | uint16(emu.Read(emu.CPU.PC))
 	v := emu.CPU.GetHalve(cpu.A)
-	emu.RAM.SetByte(v, a)
+	emu.Write(v, a)
 	emu.CPU.PC++
+	return 3
 }
 
 // LD A, (HL-): Load accumulator (indirect HL, decrement)
 //
 // Loads the memory value in the specified index at HL
 // into the register A. Then, HL is decremented by 1.
-func LDaHLm(emu emulator.Emulation) {
+func LDaHLm(emu emulator.Emulation) int {
 	a := emu.CPU.HL
-	v := emu.RAM.GetByte(a)
+	v := emu.Read(a)
 	emu.CPU.SetHalve(cpu.A, v)
 	emu.CPU.HL--
 	emu.CPU.PC++
+	return 2
 }
 
 // LD (HL-), A: Load from accumulator (indirect HL, decrement)
 //
 // Loads into the memory position in HL
 // the value in register A, then decrements HL.
-func LDHLam(emu emulator.Emulation) {
+func LDHLam(emu emulator.Emulation) int {
 	a := emu.CPU.HL
 	v := emu.CPU.GetHalve(cpu.A)
-	emu.RAM.SetByte(v, a)
+	emu.Write(v, a)
 	emu.CPU.HL--
 	emu.CPU.PC++
+	return 2
 }
 
 // LD A, (HL+): Load accumulator (indirect HL, increment)
 //
 // Loads the memory value in the specified index at HL
 // into the register A. Then, HL is incremented by 1.
-func LDaHLp(emu emulator.Emulation) {
+func LDaHLp(emu emulator.Emulation) int {
 	a := emu.CPU.HL
-	v := emu.RAM.GetByte(a)
+	v := emu.Read(a)
 	emu.CPU.SetHalve(cpu.A, v)
 	emu.CPU.HL++
 	emu.CPU.PC++
+	return 2
 }
 
 // LD (HL-), A: Load from accumulator (indirect HL, increment)
 //
 // Loads into the memory position in HL
 // the value in register A, then increments HL.
-func LDHLap(emu emulator.Emulation) {
+func LDHLap(emu emulator.Emulation) int {
 	a := emu.CPU.HL
 	v := emu.CPU.GetHalve(cpu.A)
-	emu.RAM.SetByte(v, a)
+	emu.Write(v, a)
 	emu.CPU.HL++
 	emu.CPU.PC++
+	return 2
 }
 
 // LD rr, nn: Load 16-bit register / register pair
 //
 // Loads into rr the immediate data in the next
 // two registers from the instruction.
-func LDrrnn(rr cpu.Register, emu emulator.Emulation) {
+func LDrrnn(rr cpu.Register, emu emulator.Emulation) int {
 	emu.CPU.PC++
-	nLo := emu.RAM.GetByte(emu.CPU.PC)
+	nLo := emu.Read(emu.CPU.PC)
 	emu.CPU.PC++
-	nHi := emu.RAM.GetByte(emu.CPU.PC)
+	nHi := emu.Read(emu.CPU.PC)
 	v := uint16(nHi)<<8 | uint16(nLo)
 	emu.CPU.SetReg(rr, v)
 	emu.CPU.PC++
+	return 3
 }
 
 // LD (nn), SP: Load from stack pointer (direct)
 //
 // Loads into the memory address defined in nn the
 // value inside SP.
-func LDnnSP(emu emulator.Emulation) {
+func LDnnSP(emu emulator.Emulation) int {
 	emu.CPU.PC++
-	nLo := emu.RAM.GetByte(emu.CPU.PC)
+	nLo := emu.Read(emu.CPU.PC)
 	emu.CPU.PC++
-	nHi := emu.RAM.GetByte(emu.CPU.PC)
+	nHi := emu.Read(emu.CPU.PC)
 
 	a := uint16(nHi)<<8 | uint16(nLo)
 	v := emu.CPU.GetReg(cpu.SP)
 
-	emu.RAM.Set16Bit(v, a)
+	emu.Write16(v, a)
 	emu.CPU.PC++
+	return 5
 }
 
 // LD SP, HL: Load stack pointer from HL
 //
 // Loads the value in HL into SP.
-func LDSPHL(emu emulator.Emulation) {
+func LDSPHL(emu emulator.Emulation) int {
 	v := emu.CPU.HL
 	emu.CPU.SP = v
 	emu.CPU.PC++
+	return 2
 }
 
 // PUSH rr: Push to stack
 //
 // Pushes the value of register rr to
 // the stack.
-func PUSHrr(rr cpu.Register, emu emulator.Emulation) {
-	a := emu.CPU.GetReg(cpu.SP)
+func PUSHrr(rr cpu.Register, emu emulator.Emulation) int {
 	v := emu.CPU.GetReg(rr)
-	emu.CPU.SP--
-	emu.RAM.Set16Bit(v, a)
-	emu.CPU.SP--
+	emu.Idle()
+	push16(v, emu)
 	emu.CPU.PC++
+	return 4
 }
 
 // POP rr: Pop from stack
 //
-// Pops from the stack into rr.
-func POPrr(rr cpu.Register, emu emulator.Emulation) {
-	a := emu.CPU.GetReg(cpu.SP)
-	v := emu.RAM.Get16Bit(a)
-	emu.CPU.SP += 2
+// Pops from the stack into rr. When rr is AF, the
+// low nibble of F always reads as 0.
+func POPrr(rr cpu.Register, emu emulator.Emulation) int {
+	v := pop16(emu)
+	if rr == cpu.AF {
+		v &= 0xFFF0
+	}
 	emu.CPU.SetReg(rr, v)
 	emu.CPU.PC++
+	return 3
 }
 
 // LD HL, SP+e: Load HL from adjusted stack pointer
 //
 // Loads the sum of e (next value to the instruction in memory) and SP
 // into HL.
-func LDHLSPpe(emu emulator.Emulation) {
+func LDHLSPpe(emu emulator.Emulation) int {
 	emu.CPU.PC++
-	e := int8(emu.RAM.GetByte(emu.CPU.PC)) // casted so its signed
+	e := int8(emu.Read(emu.CPU.PC)) // casted so its signed
 	// Casted into int 32 to respect e's signed value
 	v := int32(emu.CPU.GetReg(cpu.SP)) + int32(e)
 
@@ -430,13 +476,14 @@ func LDHLSPpe(emu emulator.Emulation) {
 	emu.CPU.SetFlag(false, cpu.FlagN)
 
 	emu.CPU.PC++
+	return 3
 }
 
 // ADD r: Add (register)
 //
 // Loads into register A the value of A + the value of
 // the specified register (r).
-func ADDr(r cpu.Halve, emu emulator.Emulation) {
+func ADDr(r cpu.Halve, emu emulator.Emulation) int {
 	v, carry, hCarry := add8(emu.CPU.GetHalve(cpu.A), emu.CPU.GetHalve(r))
 	emu.CPU.SetHalve(cpu.A, v)
 
@@ -446,15 +493,16 @@ func ADDr(r cpu.Halve, emu emulator.Emulation) {
 	emu.CPU.SetFlag(hCarry, cpu.FlagH)
 
 	emu.CPU.PC++
+	return 1
 }
 
 // ADD (HL): Add (indirect HL)
 //
 // Loads into register A the value of A + the value of
 // in memory in address HL.
-func ADDHL(emu emulator.Emulation) {
+func ADDHL(emu emulator.Emulation) int {
 	a := emu.CPU.GetReg(cpu.HL)
-	v, carry, hCarry := add8(emu.CPU.GetHalve(cpu.A), emu.RAM.GetByte(a))
+	v, carry, hCarry := add8(emu.CPU.GetHalve(cpu.A), emu.Read(a))
 	emu.CPU.SetHalve(cpu.A, v)
 
 	emu.CPU.SetFlag(v == 0, cpu.FlagZ)
@@ -463,16 +511,17 @@ func ADDHL(emu emulator.Emulation) {
 	emu.CPU.SetFlag(hCarry, cpu.FlagH)
 
 	emu.CPU.PC++
+	return 2
 }
 
 // ADD n: Add (immediate)
 //
 // Loads into register A the value of A + the value of
 // in memory next to the instruction.
-func ADDn(emu emulator.Emulation) {
+func ADDn(emu emulator.Emulation) int {
 	emu.CPU.PC++
 	a := emu.CPU.PC
-	v, carry, hCarry := add8(emu.CPU.GetHalve(cpu.A), emu.RAM.GetByte(a))
+	v, carry, hCarry := add8(emu.CPU.GetHalve(cpu.A), emu.Read(a))
 	emu.CPU.SetHalve(cpu.A, v)
 
 	emu.CPU.SetFlag(v == 0, cpu.FlagZ)
@@ -481,13 +530,14 @@ func ADDn(emu emulator.Emulation) {
 	emu.CPU.SetFlag(hCarry, cpu.FlagH)
 
 	emu.CPU.PC++
+	return 2
 }
 
 // ADC r: Add with carry (register)
 //
 // Loads into register A the value of A + the value of
 // register r + the carry flag.
-func ADCr(r cpu.Halve, emu emulator.Emulation) {
+func ADCr(r cpu.Halve, emu emulator.Emulation) int {
 	var f byte = 0
 	if emu.CPU.IsFlag(cpu.FlagC) {
 		f = 1
@@ -508,13 +558,14 @@ func ADCr(r cpu.Halve, emu emulator.Emulation) {
 	emu.CPU.SetFlag(hasH1 || hasH2, cpu.FlagH)
 
 	emu.CPU.PC++
+	return 1
 }
 
 // ADC (HL): Add with carry (indirect HL)
 //
 // Loads into register A the value of A + the value in address HL +
 // the carry flag.
-func ADCHL(emu emulator.Emulation) {
+func ADCHL(emu emulator.Emulation) int {
 	var f byte = 0
 	if emu.CPU.IsFlag(cpu.FlagC) {
 		f = 1
@@ -523,7 +574,7 @@ func ADCHL(emu emulator.Emulation) {
 
 	// First we compute the register sum, then we add 1 if the C flag
 	// was set
-	v1, hasC1, hasH1 := add8(emu.CPU.GetHalve(cpu.A), emu.RAM.GetByte(a))
+	v1, hasC1, hasH1 := add8(emu.CPU.GetHalve(cpu.A), emu.Read(a))
 	v2, hasC2, hasH2 := add8(v1, f)
 
 	emu.CPU.SetHalve(cpu.A, v2)
@@ -536,13 +587,14 @@ func ADCHL(emu emulator.Emulation) {
 	emu.CPU.SetFlag(hasH1 || hasH2, cpu.FlagH)
 
 	emu.CPU.PC++
+	return 2
 }
 
 // ADC n: Add with carry (immediate)
 //
 // Loads into register A the value of A + the value in memory
 // next to the instruction + the carry flag.
-func ADCn(emu emulator.Emulation) {
+func ADCn(emu emulator.Emulation) int {
 	emu.CPU.PC++
 	var f byte = 0
 	if emu.CPU.IsFlag(cpu.FlagC) {
@@ -552,7 +604,7 @@ func ADCn(emu emulator.Emulation) {
 
 	// First we compute the register sum, then we add 1 if the C flag
 	// was set
-	v1, hasC1, hasH1 := add8(emu.CPU.GetHalve(cpu.A), emu.RAM.GetByte(a))
+	v1, hasC1, hasH1 := add8(emu.CPU.GetHalve(cpu.A), emu.Read(a))
 	v2, hasC2, hasH2 := add8(v1, f)
 
 	emu.CPU.SetHalve(cpu.A, v2)
@@ -565,13 +617,14 @@ func ADCn(emu emulator.Emulation) {
 	emu.CPU.SetFlag(hasH1 || hasH2, cpu.FlagH)
 
 	emu.CPU.PC++
+	return 2
 }
 
 // SUB r: Subtract (register)
 //
 // Loads into register A the value of A - the value in
 // register r.
-func SUBr(r cpu.Halve, emu emulator.Emulation) {
+func SUBr(r cpu.Halve, emu emulator.Emulation) int {
 	v, carry, hCarry := sub8(emu.CPU.GetHalve(cpu.A), emu.CPU.GetHalve(r))
 	emu.CPU.SetHalve(cpu.A, v)
 
@@ -581,15 +634,16 @@ func SUBr(r cpu.Halve, emu emulator.Emulation) {
 	emu.CPU.SetFlag(carry, cpu.FlagC)
 
 	emu.CPU.PC++
+	return 1
 }
 
 // SUB (HL): Subtract (indirect HL)
 //
 // Loads into register A the value of A - the value in
 // memory in address HL
-func SUBHL(emu emulator.Emulation) {
+func SUBHL(emu emulator.Emulation) int {
 	a := emu.CPU.GetReg(cpu.HL)
-	v, carry, hCarry := sub8(emu.CPU.GetHalve(cpu.A), emu.RAM.GetByte(a))
+	v, carry, hCarry := sub8(emu.CPU.GetHalve(cpu.A), emu.Read(a))
 	emu.CPU.SetHalve(cpu.A, v)
 
 	emu.CPU.SetFlag(v == 0, cpu.FlagZ)
@@ -598,16 +652,17 @@ func SUBHL(emu emulator.Emulation) {
 	emu.CPU.SetFlag(carry, cpu.FlagC)
 
 	emu.CPU.PC++
+	return 2
 }
 
 // SUB n: Subtract (immediate)
 //
 // Loads into register A the value of A - the value in
 // memory next to the instruction
-func SUBn(emu emulator.Emulation) {
+func SUBn(emu emulator.Emulation) int {
 	emu.CPU.PC++
 	a := emu.CPU.GetReg(cpu.PC)
-	v, carry, hCarry := sub8(emu.CPU.GetHalve(cpu.A), emu.RAM.GetByte(a))
+	v, carry, hCarry := sub8(emu.CPU.GetHalve(cpu.A), emu.Read(a))
 	emu.CPU.SetHalve(cpu.A, v)
 
 	emu.CPU.SetFlag(v == 0, cpu.FlagZ)
@@ -616,13 +671,14 @@ func SUBn(emu emulator.Emulation) {
 	emu.CPU.SetFlag(carry, cpu.FlagC)
 
 	emu.CPU.PC++
+	return 2
 }
 
 // SBC r: Subtract with carry (register)
 //
 // Loads into register A the value of A - the value in
 // register r - flag C
-func SBCr(r cpu.Halve, emu emulator.Emulation) {
+func SBCr(r cpu.Halve, emu emulator.Emulation) int {
 	var f byte = 0
 	if emu.CPU.IsFlag(cpu.FlagC) {
 		f = 1
@@ -643,13 +699,14 @@ func SBCr(r cpu.Halve, emu emulator.Emulation) {
 	emu.CPU.SetFlag(hasH1 || hasH2, cpu.FlagH)
 
 	emu.CPU.PC++
+	return 1
 }
 
 // SBC (HL): Subtract with carry (indirect HL)
 //
 // Loads into register A the value of A - the memory value
 // in address HL - flag C
-func SBCHL(emu emulator.Emulation) {
+func SBCHL(emu emulator.Emulation) int {
 	var f byte = 0
 	if emu.CPU.IsFlag(cpu.FlagC) {
 		f = 1
@@ -659,7 +716,7 @@ func SBCHL(emu emulator.Emulation) {
 
 	// First we compute the register sum, then we add 1 if the C flag
 	// was set
-	v1, hasC1, hasH1 := sub8(emu.CPU.GetHalve(cpu.A), emu.RAM.GetByte(a))
+	v1, hasC1, hasH1 := sub8(emu.CPU.GetHalve(cpu.A), emu.Read(a))
 	v2, hasC2, hasH2 := sub8(v1, f)
 
 	emu.CPU.SetHalve(cpu.A, v2)
@@ -672,13 +729,14 @@ func SBCHL(emu emulator.Emulation) {
 	emu.CPU.SetFlag(hasH1 || hasH2, cpu.FlagH)
 
 	emu.CPU.PC++
+	return 2
 }
 
 // SBC n: Subtract with carry (immediate)
 //
 // Loads into register A the value of A - the memory value next to
 // the instruction - flag C.
-func SBCn(emu emulator.Emulation) {
+func SBCn(emu emulator.Emulation) int {
 	var f byte = 0
 	if emu.CPU.IsFlag(cpu.FlagC) {
 		f = 1
@@ -689,7 +747,7 @@ func SBCn(emu emulator.Emulation) {
 
 	// First we compute the register sum, then we add 1 if the C flag
 	// was set
-	v1, hasC1, hasH1 := sub8(emu.CPU.GetHalve(cpu.A), emu.RAM.GetByte(a))
+	v1, hasC1, hasH1 := sub8(emu.CPU.GetHalve(cpu.A), emu.Read(a))
 	v2, hasC2, hasH2 := sub8(v1, f)
 
 	emu.CPU.SetHalve(cpu.A, v2)
@@ -702,6 +760,7 @@ func SBCn(emu emulator.Emulation) {
 	emu.CPU.SetFlag(hasH1 || hasH2, cpu.FlagH)
 
 	emu.CPU.PC++
+	return 2
 }
 
 // CP r: Compare (register)
@@ -710,7 +769,7 @@ func SBCn(emu emulator.Emulation) {
 // updates the flags accordingly.
 //
 // Identical to SUBr, bit without modifying A.
-func CPr(r cpu.Halve, emu emulator.Emulation) {
+func CPr(r cpu.Halve, emu emulator.Emulation) int {
 	v, carry, hCarry := sub8(emu.CPU.GetHalve(cpu.A), emu.CPU.GetHalve(r))
 
 	emu.CPU.SetFlag(v == 0, cpu.FlagZ)
@@ -719,6 +778,7 @@ func CPr(r cpu.Halve, emu emulator.Emulation) {
 	emu.CPU.SetFlag(carry, cpu.FlagC)
 
 	emu.CPU.PC++
+	return 1
 }
 
 // CP (HL): Compare (indirect HL)
@@ -727,9 +787,9 @@ func CPr(r cpu.Halve, emu emulator.Emulation) {
 // updates the flags accordingly.
 //
 // Identical to SUBHL, bit without modifying A.
-func CPHL(emu emulator.Emulation) {
+func CPHL(emu emulator.Emulation) int {
 	a := emu.CPU.GetReg(cpu.HL)
-	v, carry, hCarry := sub8(emu.CPU.GetHalve(cpu.A), emu.RAM.GetByte(a))
+	v, carry, hCarry := sub8(emu.CPU.GetHalve(cpu.A), emu.Read(a))
 
 	emu.CPU.SetFlag(v == 0, cpu.FlagZ)
 	emu.CPU.SetFlag(true, cpu.FlagN)
@@ -737,6 +797,7 @@ func CPHL(emu emulator.Emulation) {
 	emu.CPU.SetFlag(carry, cpu.FlagC)
 
 	emu.CPU.PC++
+	return 2
 }
 
 // CP n: Compare (immediate)
@@ -745,10 +806,10 @@ func CPHL(emu emulator.Emulation) {
 // the instruction, and updates the flags accordingly.
 //
 // Identical to SUBn, bit without modifying A.
-func CPn(emu emulator.Emulation) {
+func CPn(emu emulator.Emulation) int {
 	emu.CPU.PC++
 	a := emu.CPU.GetReg(cpu.PC)
-	v, carry, hCarry := sub8(emu.CPU.GetHalve(cpu.A), emu.RAM.GetByte(a))
+	v, carry, hCarry := sub8(emu.CPU.GetHalve(cpu.A), emu.Read(a))
 
 	emu.CPU.SetFlag(v == 0, cpu.FlagZ)
 	emu.CPU.SetFlag(true, cpu.FlagN)
@@ -756,12 +817,13 @@ func CPn(emu emulator.Emulation) {
 	emu.CPU.SetFlag(carry, cpu.FlagC)
 
 	emu.CPU.PC++
+	return 2
 }
 
 // INC r: Increment (register)
 //
 // Increments by 1 the value of register r.
-func INCr(r cpu.Halve, emu emulator.Emulation) {
+func INCr(r cpu.Halve, emu emulator.Emulation) int {
 	v, _, hCarry := add8(emu.CPU.GetHalve(r), 1)
 	emu.CPU.SetHalve(r, v)
 
@@ -769,26 +831,28 @@ func INCr(r cpu.Halve, emu emulator.Emulation) {
 	emu.CPU.SetFlag(false, cpu.FlagN)
 	emu.CPU.SetFlag(hCarry, cpu.FlagH)
 	emu.CPU.PC++
+	return 1
 }
 
 // INC (HL): Increment (indirect HL)
 //
 // Increments by 1 the value in memory in address HL.
-func INCHL(emu emulator.Emulation) {
+func INCHL(emu emulator.Emulation) int {
 	a := emu.CPU.GetReg(cpu.HL)
-	v, _, hCarry := add8(emu.RAM.GetByte(a), 1)
-	emu.RAM.SetByte(v, a)
+	v, _, hCarry := add8(emu.Read(a), 1)
+	emu.Write(v, a)
 
 	emu.CPU.SetFlag(v == 0, cpu.FlagZ)
 	emu.CPU.SetFlag(false, cpu.FlagN)
 	emu.CPU.SetFlag(hCarry, cpu.FlagH)
 	emu.CPU.PC++
+	return 3
 }
 
 // DEC r: Increment (register)
 //
 // Decrements by 1 the value of register r.
-func DECr(r cpu.Halve, emu emulator.Emulation) {
+func DECr(r cpu.Halve, emu emulator.Emulation) int {
 	v, _, hCarry := sub8(emu.CPU.GetHalve(r), 1)
 	emu.CPU.SetHalve(r, v)
 
@@ -796,27 +860,29 @@ func DECr(r cpu.Halve, emu emulator.Emulation) {
 	emu.CPU.SetFlag(true, cpu.FlagN)
 	emu.CPU.SetFlag(hCarry, cpu.FlagH)
 	emu.CPU.PC++
+	return 1
 }
 
 // DEC (HL): Increment (indirect HL)
 //
 // Decrements by 1 the value in memory in address HL.
-func DECHL(emu emulator.Emulation) {
+func DECHL(emu emulator.Emulation) int {
 	a := emu.CPU.GetReg(cpu.HL)
-	v, _, hCarry := sub8(emu.RAM.GetByte(a), 1)
-	emu.RAM.SetByte(v, a)
+	v, _, hCarry := sub8(emu.Read(a), 1)
+	emu.Write(v, a)
 
 	emu.CPU.SetFlag(v == 0, cpu.FlagZ)
 	emu.CPU.SetFlag(true, cpu.FlagN)
 	emu.CPU.SetFlag(hCarry, cpu.FlagH)
 	emu.CPU.PC++
+	return 3
 }
 
 // AND r: Bitwise AND (register)
 //
 // Sets in register A the value of an AND operation
 // between register A and r.
-func ANDr(r cpu.Halve, emu emulator.Emulation) {
+func ANDr(r cpu.Halve, emu emulator.Emulation) int {
 	// NOTE: According to Game Boy references,
 	// the flag H is always set in AND operations.
 	// I have not found the reason as to why this is done.
@@ -829,6 +895,7 @@ func ANDr(r cpu.Halve, emu emulator.Emulation) {
 	emu.CPU.SetFlag(true, cpu.FlagH)
 	emu.CPU.SetFlag(false, cpu.FlagC)
 	emu.CPU.PC++
+	return 1
 }
 
 // AND (HL): Bitwise AND (indirect HL)
@@ -836,13 +903,13 @@ func ANDr(r cpu.Halve, emu emulator.Emulation) {
 // Sets in register A the value of an AND operation
 // between register A and the value in memory in
 // address HL.
-func ANDHL(emu emulator.Emulation) {
+func ANDHL(emu emulator.Emulation) int {
 	// NOTE: According to Game Boy references,
 	// the flag H is always set in AND operations.
 	// I have not found the reason as to why this is done.
 	a := emu.CPU.GetReg(cpu.HL)
 
-	v := emu.RAM.GetByte(a) & emu.CPU.GetHalve(cpu.A)
+	v := emu.Read(a) & emu.CPU.GetHalve(cpu.A)
 	emu.CPU.SetHalve(cpu.A, v)
 
 	emu.CPU.SetFlag(v == 0, cpu.FlagZ)
@@ -850,6 +917,7 @@ func ANDHL(emu emulator.Emulation) {
 	emu.CPU.SetFlag(true, cpu.FlagH)
 	emu.CPU.SetFlag(false, cpu.FlagC)
 	emu.CPU.PC++
+	return 2
 }
 
 // AND n: Bitwise AND (immediate)
@@ -857,7 +925,7 @@ func ANDHL(emu emulator.Emulation) {
 // Sets in register A the value of an AND operation
 // between register A and the value in memory in
 // the address next to the instruction..
-func ANDn(emu emulator.Emulation) {
+func ANDn(emu emulator.Emulation) int {
 	// NOTE: According to Game Boy references,
 	// the flag H is always set in AND operations.
 	// I have not found the reason as to why this is done.
@@ -865,7 +933,7 @@ func ANDn(emu emulator.Emulation) {
 	emu.CPU.PC++
 	a := emu.CPU.GetReg(cpu.PC)
 
-	v := emu.RAM.GetByte(a) & emu.CPU.GetHalve(cpu.A)
+	v := emu.Read(a) & emu.CPU.GetHalve(cpu.A)
 	emu.CPU.SetHalve(cpu.A, v)
 
 	emu.CPU.SetFlag(v == 0, cpu.FlagZ)
@@ -873,13 +941,14 @@ func ANDn(emu emulator.Emulation) {
 	emu.CPU.SetFlag(true, cpu.FlagH)
 	emu.CPU.SetFlag(false, cpu.FlagC)
 	emu.CPU.PC++
+	return 2
 }
 
 // OR r: Bitwise OR (register)
 //
 // Sets in register A the value of an OR operation
 // between register A and r.
-func ORr(r cpu.Halve, emu emulator.Emulation) {
+func ORr(r cpu.Halve, emu emulator.Emulation) int {
 	v := emu.CPU.GetHalve(r) | emu.CPU.GetHalve(cpu.A)
 	emu.CPU.SetHalve(cpu.A, v)
 
@@ -888,6 +957,7 @@ func ORr(r cpu.Halve, emu emulator.Emulation) {
 	emu.CPU.SetFlag(false, cpu.FlagH)
 	emu.CPU.SetFlag(false, cpu.FlagC)
 	emu.CPU.PC++
+	return 1
 }
 
 // OR (HL): Bitwise OR (indirect HL)
@@ -895,10 +965,10 @@ func ORr(r cpu.Halve, emu emulator.Emulation) {
 // Sets in register A the value of an OR operation
 // between register A and the value in memory in
 // address HL.
-func ORHL(emu emulator.Emulation) {
+func ORHL(emu emulator.Emulation) int {
 	a := emu.CPU.GetReg(cpu.HL)
 
-	v := emu.RAM.GetByte(a) | emu.CPU.GetHalve(cpu.A)
+	v := emu.Read(a) | emu.CPU.GetHalve(cpu.A)
 	emu.CPU.SetHalve(cpu.A, v)
 
 	emu.CPU.SetFlag(v == 0, cpu.FlagZ)
@@ -906,6 +976,7 @@ func ORHL(emu emulator.Emulation) {
 	emu.CPU.SetFlag(false, cpu.FlagH)
 	emu.CPU.SetFlag(false, cpu.FlagC)
 	emu.CPU.PC++
+	return 2
 }
 
 // OR n: Bitwise OR (immediate)
@@ -913,11 +984,11 @@ func ORHL(emu emulator.Emulation) {
 // Sets in register A the value of an OR operation
 // between register A and the value in memory in
 // the address next to the instruction..
-func ORn(emu emulator.Emulation) {
+func ORn(emu emulator.Emulation) int {
 	emu.CPU.PC++
 	a := emu.CPU.GetReg(cpu.PC)
 
-	v := emu.RAM.GetByte(a) | emu.CPU.GetHalve(cpu.A)
+	v := emu.Read(a) | emu.CPU.GetHalve(cpu.A)
 	emu.CPU.SetHalve(cpu.A, v)
 
 	emu.CPU.SetFlag(v == 0, cpu.FlagZ)
@@ -925,13 +996,14 @@ func ORn(emu emulator.Emulation) {
 	emu.CPU.SetFlag(false, cpu.FlagH)
 	emu.CPU.SetFlag(false, cpu.FlagC)
 	emu.CPU.PC++
+	return 2
 }
 
 // XOR r: Bitwise XOR (register)
 //
 // Sets in register A the value of an XOR operation
 // between register A and r.
-func XORr(r cpu.Halve, emu emulator.Emulation) {
+func XORr(r cpu.Halve, emu emulator.Emulation) int {
 	v := emu.CPU.GetHalve(r) ^ emu.CPU.GetHalve(cpu.A)
 	emu.CPU.SetHalve(cpu.A, v)
 
@@ -940,6 +1012,7 @@ func XORr(r cpu.Halve, emu emulator.Emulation) {
 	emu.CPU.SetFlag(false, cpu.FlagH)
 	emu.CPU.SetFlag(false, cpu.FlagC)
 	emu.CPU.PC++
+	return 1
 }
 
 // XOR (HL): Bitwise XOR (indirect HL)
@@ -947,10 +1020,10 @@ func XORr(r cpu.Halve, emu emulator.Emulation) {
 // Sets in register A the value of an XOR operation
 // between register A and the value in memory in
 // address HL.
-func XORHL(emu emulator.Emulation) {
+func XORHL(emu emulator.Emulation) int {
 	a := emu.CPU.GetReg(cpu.HL)
 
-	v := emu.RAM.GetByte(a) ^ emu.CPU.GetHalve(cpu.A)
+	v := emu.Read(a) ^ emu.CPU.GetHalve(cpu.A)
 	emu.CPU.SetHalve(cpu.A, v)
 
 	emu.CPU.SetFlag(v == 0, cpu.FlagZ)
@@ -958,6 +1031,7 @@ func XORHL(emu emulator.Emulation) {
 	emu.CPU.SetFlag(false, cpu.FlagH)
 	emu.CPU.SetFlag(false, cpu.FlagC)
 	emu.CPU.PC++
+	return 2
 }
 
 // XOR n: Bitwise XOR (immediate)
@@ -965,11 +1039,11 @@ func XORHL(emu emulator.Emulation) {
 // Sets in register A the value of an XOR operation
 // between register A and the value in memory in
 // the address next to the instruction.
-func XORn(emu emulator.Emulation) {
+func XORn(emu emulator.Emulation) int {
 	emu.CPU.PC++
 	a := emu.CPU.GetReg(cpu.PC)
 
-	v := emu.RAM.GetByte(a) ^ emu.CPU.GetHalve(cpu.A)
+	v := emu.Read(a) ^ emu.CPU.GetHalve(cpu.A)
 	emu.CPU.SetHalve(cpu.A, v)
 
 	emu.CPU.SetFlag(v == 0, cpu.FlagZ)
@@ -977,27 +1051,30 @@ func XORn(emu emulator.Emulation) {
 	emu.CPU.SetFlag(false, cpu.FlagH)
 	emu.CPU.SetFlag(false, cpu.FlagC)
 	emu.CPU.PC++
+	return 2
 }
 
 // CCF: Complement carry flag
 //
 // Flips the value of the carry flag
 // and clears N and H.
-func CCF(emu emulator.Emulation) {
-	emu.CPU.SetFlag(emu.CPU.IsFlag(cpu.FlagC), cpu.FlagC)
+func CCF(emu emulator.Emulation) int {
+	emu.CPU.SetFlag(!emu.CPU.IsFlag(cpu.FlagC), cpu.FlagC)
 	emu.CPU.SetFlag(false, cpu.FlagN)
 	emu.CPU.SetFlag(false, cpu.FlagH)
 	emu.CPU.PC++
+	return 1
 }
 
 // SCF: Set carry flag
 //
 //	Set the carry flag and clears N and H.
-func SCF(emu emulator.Emulation) {
+func SCF(emu emulator.Emulation) int {
 	emu.CPU.SetFlag(true, cpu.FlagC)
 	emu.CPU.SetFlag(false, cpu.FlagN)
 	emu.CPU.SetFlag(false, cpu.FlagH)
 	emu.CPU.PC++
+	return 1
 }
 
 // DAA: Decimal adjust accumulator
@@ -1005,7 +1082,7 @@ func SCF(emu emulator.Emulation) {
 // Adjusts the value of A to turn it into a Binary Coded
 // Decimal (BCD) according to the previously performed
 // arithmetic operation.
-func DAA(emu emulator.Emulation) {
+func DAA(emu emulator.Emulation) int {
 	// NOTE: This instruction is rather unintuitive
 	// if the context of BCD is unknown. For more
 	// information about the workings of this
@@ -1045,62 +1122,67 @@ func DAA(emu emulator.Emulation) {
 	emu.CPU.SetFlag(false, cpu.FlagH)
 	emu.CPU.SetFlag(resultCarry, cpu.FlagC)
 	emu.CPU.PC++
+	return 1
 }
 
 // CPL: Complement accumulator
 //
 // Complements register A (flips its bits)
 // and sets flags N and H
-func CPL(emu emulator.Emulation) {
+func CPL(emu emulator.Emulation) int {
 	v := ^emu.CPU.GetHalve(cpu.A)
 
 	emu.CPU.SetHalve(cpu.A, v)
 	emu.CPU.SetFlag(true, cpu.FlagN)
 	emu.CPU.SetFlag(true, cpu.FlagH)
 	emu.CPU.PC++
+	return 1
 }
 
 // INC rr: Increment 16-bit register
 //
 // Increments by 1 the value of 16-bit register rr
-func INCrr(rr cpu.Register, emu emulator.Emulation) {
+func INCrr(rr cpu.Register, emu emulator.Emulation) int {
 	v := emu.CPU.GetReg(rr) + 1
 
 	emu.CPU.SetReg(rr, v)
 	emu.CPU.PC++
+	return 2
 }
 
 // DEC rr: Decrement 16-bit register
 //
 // Decrements by 1 the value of 16-bit register rr
-func DECrr(rr cpu.Register, emu emulator.Emulation) {
+func DECrr(rr cpu.Register, emu emulator.Emulation) int {
 	v := emu.CPU.GetReg(rr) - 1
 
 	emu.CPU.SetReg(rr, v)
 	emu.CPU.PC++
+	return 2
 }
 
 // ADD HL, rr: Add (16-bit register)
 //
 // Sets in register HL the value of HL + rr
-func ADDHLrr(rr cpu.Register, emu emulator.Emulation) {
+func ADDHLrr(rr cpu.Register, emu emulator.Emulation) int {
 	v, carry, hCarry := add16(emu.CPU.GetReg(cpu.HL), emu.CPU.GetReg(rr))
 	emu.CPU.SetReg(cpu.HL, v)
 
-	emu.CPU.SetFlag(v == 0, cpu.FlagZ)
+	// Z is left untouched by 16-bit additions
 	emu.CPU.SetFlag(false, cpu.FlagN)
 	emu.CPU.SetFlag(carry, cpu.FlagC)
 	emu.CPU.SetFlag(hCarry, cpu.FlagH)
 
 	emu.CPU.PC++
+	return 2
 }
 
 // ADD SP, e: Add to stack pointer (relative)
 //
 // Sets in register SP the value of SP + e (8 bit)
-func ADDSPpe(emu emulator.Emulation) {
+func ADDSPpe(emu emulator.Emulation) int {
 	emu.CPU.PC++
-	e := int8(emu.RAM.GetByte(emu.CPU.PC)) // casted so its signed
+	e := int8(emu.Read(emu.CPU.PC)) // casted so its signed
 	sp := emu.CPU.GetReg(cpu.SP)
 
 	// Here we cast into int 32 to respect e's signed value
@@ -1125,13 +1207,14 @@ func ADDSPpe(emu emulator.Emulation) {
 	emu.CPU.SetFlag(false, cpu.FlagN)
 
 	emu.CPU.PC++
+	return 4
 }
 
 // RLCA: Rotate left circular (accumulator)
 //
 // Shifts register A to the left once, and
 // bit 7 is copied into the C flag and bit 0.
-func RLCA(emu emulator.Emulation) {
+func RLCA(emu emulator.Emulation) int {
 	a := emu.CPU.GetHalve(cpu.A)
 	// Moves bit 7 to the lowest position,
 	// esentially rotating it
@@ -1139,16 +1222,20 @@ func RLCA(emu emulator.Emulation) {
 	v := a<<1 | rot
 
 	emu.CPU.SetHalve(cpu.A, v)
+	emu.CPU.SetFlag(false, cpu.FlagZ)
+	emu.CPU.SetFlag(false, cpu.FlagN)
+	emu.CPU.SetFlag(false, cpu.FlagH)
 	// If bit 7 was 1, we set flag C
 	emu.CPU.SetFlag(rot > 0, cpu.FlagC)
 	emu.CPU.PC++
+	return 1
 }
 
 // RRCA: Rotate right circular (accumulator)
 //
 // Shifts register A to the right once, and
 // bit 0 is copied into the C flag and bit 7.
-func RRCA(emu emulator.Emulation) {
+func RRCA(emu emulator.Emulation) int {
 	a := emu.CPU.GetHalve(cpu.A)
 	// Moves bit 0 to the highest position,
 	// esentially rotating it
@@ -1156,9 +1243,13 @@ func RRCA(emu emulator.Emulation) {
 	v := a>>1 | rot
 
 	emu.CPU.SetHalve(cpu.A, v)
+	emu.CPU.SetFlag(false, cpu.FlagZ)
+	emu.CPU.SetFlag(false, cpu.FlagN)
+	emu.CPU.SetFlag(false, cpu.FlagH)
 	// If bit 7 was 1, we set flag C
 	emu.CPU.SetFlag(rot > 0, cpu.FlagC)
 	emu.CPU.PC++
+	return 1
 }
 
 // RLA: Rotate left (accumulator)
@@ -1166,7 +1257,7 @@ func RRCA(emu emulator.Emulation) {
 // Shifts register A to the left once, bit 7
 // is copied into flag C, and flag C is copied
 // into bit 0.
-func RLA(emu emulator.Emulation) {
+func RLA(emu emulator.Emulation) int {
 	a := emu.CPU.GetHalve(cpu.A)
 	var rot byte
 	if emu.CPU.IsFlag(cpu.FlagC) {
@@ -1176,9 +1267,13 @@ func RLA(emu emulator.Emulation) {
 	v := a<<1 | rot
 
 	emu.CPU.SetHalve(cpu.A, v)
+	emu.CPU.SetFlag(false, cpu.FlagZ)
+	emu.CPU.SetFlag(false, cpu.FlagN)
+	emu.CPU.SetFlag(false, cpu.FlagH)
 	// If bit 7 was 1, we set flag C
 	emu.CPU.SetFlag(a>>7 > 0, cpu.FlagC)
 	emu.CPU.PC++
+	return 1
 }
 
 // RRA: Rotate right (accumulator)
@@ -1186,7 +1281,7 @@ func RLA(emu emulator.Emulation) {
 // Shifts register A to the right once, bit 0
 // is copied into flag C, and flag C is copied
 // into bit 7.
-func RRA(emu emulator.Emulation) {
+func RRA(emu emulator.Emulation) int {
 	a := emu.CPU.GetHalve(cpu.A)
 	var rot byte
 	if emu.CPU.IsFlag(cpu.FlagC) {
@@ -1196,16 +1291,20 @@ func RRA(emu emulator.Emulation) {
 	v := a>>1 | rot
 
 	emu.CPU.SetHalve(cpu.A, v)
+	emu.CPU.SetFlag(false, cpu.FlagZ)
+	emu.CPU.SetFlag(false, cpu.FlagN)
+	emu.CPU.SetFlag(false, cpu.FlagH)
 	// If bit 7 was 1, we set flag C
 	emu.CPU.SetFlag(a<<7 > 0, cpu.FlagC)
 	emu.CPU.PC++
+	return 1
 }
 
 // RLC r: Rotate left circular (reigster)
 //
 // Shifts register r to the left once, and
 // bit 7 is copied into the C flag and bit 0.
-func RLCr(r cpu.Halve, emu emulator.Emulation) {
+func RLCr(r cpu.Halve, emu emulator.Emulation) int {
 	v := emu.CPU.GetHalve(r)
 	// Moves bit 7 to the lowest position,
 	// esentially rotating it
@@ -1213,34 +1312,42 @@ func RLCr(r cpu.Halve, emu emulator.Emulation) {
 	result := v<<1 | rot
 
 	emu.CPU.SetHalve(r, result)
+	emu.CPU.SetFlag(result == 0, cpu.FlagZ)
+	emu.CPU.SetFlag(false, cpu.FlagN)
+	emu.CPU.SetFlag(false, cpu.FlagH)
 	// If bit 7 was 1, we set flag C
 	emu.CPU.SetFlag(rot > 0, cpu.FlagC)
 	emu.CPU.PC++
+	return 2
 }
 
 // RLC (HL): Rotate left circular (indirect HL)
 //
 // Shifts the value in address HL to the left once, and
 // bit 7 is copied into the C flag and bit 0.
-func RLCHL(emu emulator.Emulation) {
+func RLCHL(emu emulator.Emulation) int {
 	a := emu.CPU.GetReg(cpu.HL)
-	v := emu.RAM.GetByte(a)
+	v := emu.Read(a)
 	// Moves bit 7 to the lowest position,
 	// esentially rotating it
 	rot := v >> 7
 	result := v<<1 | rot
 
-	emu.RAM.SetByte(result, a)
+	emu.Write(result, a)
+	emu.CPU.SetFlag(result == 0, cpu.FlagZ)
+	emu.CPU.SetFlag(false, cpu.FlagN)
+	emu.CPU.SetFlag(false, cpu.FlagH)
 	// If bit 7 was 1, we set flag C
 	emu.CPU.SetFlag(rot > 0, cpu.FlagC)
 	emu.CPU.PC++
+	return 4
 }
 
 // RRC r: Rotate right circular (register)
 //
 // Shifts register r to the right once, and
 // bit 0 is copied into the C flag and bit 7.
-func RRCr(r cpu.Halve, emu emulator.Emulation) {
+func RRCr(r cpu.Halve, emu emulator.Emulation) int {
 	v := emu.CPU.GetHalve(r)
 	// Moves bit 0 to the highest position,
 	// esentially rotating it
@@ -1248,27 +1355,35 @@ func RRCr(r cpu.Halve, emu emulator.Emulation) {
 	result := v>>1 | rot
 
 	emu.CPU.SetHalve(r, result)
+	emu.CPU.SetFlag(result == 0, cpu.FlagZ)
+	emu.CPU.SetFlag(false, cpu.FlagN)
+	emu.CPU.SetFlag(false, cpu.FlagH)
 	// If bit 7 was 1, we set flag C
 	emu.CPU.SetFlag(rot > 0, cpu.FlagC)
 	emu.CPU.PC++
+	return 2
 }
 
 // RRC (HL): Rotate right circular (indirect HL)
 //
 // Shifts the value in address HL to the right once, and
 // bit 0 is copied into the C flag and bit 7.
-func RRCHL(emu emulator.Emulation) {
+func RRCHL(emu emulator.Emulation) int {
 	a := emu.CPU.GetReg(cpu.HL)
-	v := emu.RAM.GetByte(a)
+	v := emu.Read(a)
 	// Moves bit 0 to the highest position,
 	// esentially rotating it
 	rot := v << 7
 	result := v>>1 | rot
 
-	emu.RAM.SetByte(result, a)
+	emu.Write(result, a)
+	emu.CPU.SetFlag(result == 0, cpu.FlagZ)
+	emu.CPU.SetFlag(false, cpu.FlagN)
+	emu.CPU.SetFlag(false, cpu.FlagH)
 	// If bit 7 was 1, we set flag C
 	emu.CPU.SetFlag(rot > 0, cpu.FlagC)
 	emu.CPU.PC++
+	return 4
 }
 
 // RL r: Rotate left (register)
@@ -1276,7 +1391,7 @@ func RRCHL(emu emulator.Emulation) {
 // Shifts register r to the left once, bit 7
 // is copied into flag C, and flag C is copied
 // into bit 0.
-func RLr(r cpu.Halve, emu emulator.Emulation) {
+func RLr(r cpu.Halve, emu emulator.Emulation) int {
 	v := emu.CPU.GetHalve(r)
 	var rot byte
 	if emu.CPU.IsFlag(cpu.FlagC) {
@@ -1286,9 +1401,13 @@ func RLr(r cpu.Halve, emu emulator.Emulation) {
 	result := v<<1 | rot
 
 	emu.CPU.SetHalve(r, result)
+	emu.CPU.SetFlag(result == 0, cpu.FlagZ)
+	emu.CPU.SetFlag(false, cpu.FlagN)
+	emu.CPU.SetFlag(false, cpu.FlagH)
 	// If bit 7 was 1, we set flag C
 	emu.CPU.SetFlag(v>>7 > 0, cpu.FlagC)
 	emu.CPU.PC++
+	return 2
 }
 
 // RL (HL): Rotate left (indirect HL)
@@ -1296,9 +1415,9 @@ func RLr(r cpu.Halve, emu emulator.Emulation) {
 // Shifts the memory value in HL to the left once, bit 7
 // is copied into flag C, and flag C is copied
 // into bit 0.
-func RLHL(emu emulator.Emulation) {
+func RLHL(emu emulator.Emulation) int {
 	a := emu.CPU.GetReg(cpu.HL)
-	v := emu.RAM.GetByte(a)
+	v := emu.Read(a)
 
 	var rot byte
 	if emu.CPU.IsFlag(cpu.FlagC) {
@@ -1307,10 +1426,14 @@ func RLHL(emu emulator.Emulation) {
 
 	result := v<<1 | rot
 
-	emu.RAM.SetByte(result, a)
+	emu.Write(result, a)
+	emu.CPU.SetFlag(result == 0, cpu.FlagZ)
+	emu.CPU.SetFlag(false, cpu.FlagN)
+	emu.CPU.SetFlag(false, cpu.FlagH)
 	// If bit 7 was 1, we set flag C
 	emu.CPU.SetFlag(v>>7 > 0, cpu.FlagC)
 	emu.CPU.PC++
+	return 4
 }
 
 // RR r: Rotate right (register)
@@ -1318,7 +1441,7 @@ func RLHL(emu emulator.Emulation) {
 // Shifts register r to the right once, bit 0
 // is copied into flag C, and flag C is copied
 // into bit 7.
-func RRr(r cpu.Halve, emu emulator.Emulation) {
+func RRr(r cpu.Halve, emu emulator.Emulation) int {
 	v := emu.CPU.GetHalve(r)
 	var rot byte
 	if emu.CPU.IsFlag(cpu.FlagC) {
@@ -1328,9 +1451,13 @@ func RRr(r cpu.Halve, emu emulator.Emulation) {
 	result := v>>1 | rot
 
 	emu.CPU.SetHalve(r, result)
+	emu.CPU.SetFlag(result == 0, cpu.FlagZ)
+	emu.CPU.SetFlag(false, cpu.FlagN)
+	emu.CPU.SetFlag(false, cpu.FlagH)
 	// If bit 7 was 1, we set flag C
 	emu.CPU.SetFlag(v<<7 > 0, cpu.FlagC)
 	emu.CPU.PC++
+	return 2
 }
 
 // RR (HL): Rotate right (indirect HL)
@@ -1338,9 +1465,9 @@ func RRr(r cpu.Halve, emu emulator.Emulation) {
 // Shifts the memory value in HL to the right once, bit 0
 // is copied into flag C, and flag C is copied
 // into bit 7.
-func RRHL(emu emulator.Emulation) {
+func RRHL(emu emulator.Emulation) int {
 	a := emu.CPU.GetReg(cpu.HL)
-	v := emu.RAM.GetByte(a)
+	v := emu.Read(a)
 	var rot byte
 	if emu.CPU.IsFlag(cpu.FlagC) {
 		rot = 0b10000000 // 0x80
@@ -1348,39 +1475,51 @@ func RRHL(emu emulator.Emulation) {
 
 	result := v>>1 | rot
 
-	emu.RAM.SetByte(result, a)
+	emu.Write(result, a)
+	emu.CPU.SetFlag(result == 0, cpu.FlagZ)
+	emu.CPU.SetFlag(false, cpu.FlagN)
+	emu.CPU.SetFlag(false, cpu.FlagH)
 	// If bit 7 was 1, we set flag C
 	emu.CPU.SetFlag(v<<7 > 0, cpu.FlagC)
 	emu.CPU.PC++
+	return 4
 }
 
 // SLA r: Shift left arithmetic (register)
 //
 // Shifts register r to the left once and bit 7
 // is copied into flag C
-func SLAr(r cpu.Halve, emu emulator.Emulation) {
+func SLAr(r cpu.Halve, emu emulator.Emulation) int {
 	v := emu.CPU.GetHalve(r)
 	result := v << 1
 
 	emu.CPU.SetHalve(r, result)
+	emu.CPU.SetFlag(result == 0, cpu.FlagZ)
+	emu.CPU.SetFlag(false, cpu.FlagN)
+	emu.CPU.SetFlag(false, cpu.FlagH)
 	// If bit 7 was 1, we set flag C
 	emu.CPU.SetFlag(v>>7 > 0, cpu.FlagC)
 	emu.CPU.PC++
+	return 2
 }
 
 // SLA (HL): Shift left arithmetic (indirect HL)
 //
 // Shifts the memory value in address HL
 // to the left once and bit 7 is copied into flag C
-func SLAHL(emu emulator.Emulation) {
+func SLAHL(emu emulator.Emulation) int {
 	a := emu.CPU.GetReg(cpu.HL)
-	v := emu.RAM.GetByte(a)
+	v := emu.Read(a)
 	result := v << 1
 
-	emu.RAM.SetByte(result, a)
+	emu.Write(result, a)
+	emu.CPU.SetFlag(result == 0, cpu.FlagZ)
+	emu.CPU.SetFlag(false, cpu.FlagN)
+	emu.CPU.SetFlag(false, cpu.FlagH)
 	// If bit 7 was 1, we set flag C
 	emu.CPU.SetFlag(v>>7 > 0, cpu.FlagC)
 	emu.CPU.PC++
+	return 4
 }
 
 // SRA r: Shift right arithmetic (register)
@@ -1390,7 +1529,7 @@ func SLAHL(emu emulator.Emulation) {
 //
 // NOTE: Bit 7 is kept as-is. Its value *will* be
 // rotated right, but bit 7 will remain unchanged.
-func SRAr(r cpu.Halve, emu emulator.Emulation) {
+func SRAr(r cpu.Halve, emu emulator.Emulation) int {
 	v := emu.CPU.GetHalve(r)
 	bit7 := (v & 0b10000000) // Masks v to clear everything but bit 7
 
@@ -1398,9 +1537,13 @@ func SRAr(r cpu.Halve, emu emulator.Emulation) {
 	result := v>>1 | bit7
 
 	emu.CPU.SetHalve(r, result)
+	emu.CPU.SetFlag(result == 0, cpu.FlagZ)
+	emu.CPU.SetFlag(false, cpu.FlagN)
+	emu.CPU.SetFlag(false, cpu.FlagH)
 	// If bit 0 was 1, we set flag C
 	emu.CPU.SetFlag(v<<7 > 0, cpu.FlagC)
 	emu.CPU.PC++
+	return 2
 }
 
 // SRA (HL): Shift right arithmetic (indirect HL)
@@ -1410,25 +1553,29 @@ func SRAr(r cpu.Halve, emu emulator.Emulation) {
 //
 // NOTE: Bit 7 is kept as-is. Its value *will* be
 // rotated right, but bit 7 will remain unchanged.
-func SRAHL(emu emulator.Emulation) {
+func SRAHL(emu emulator.Emulation) int {
 	a := emu.CPU.GetReg(cpu.HL)
-	v := emu.RAM.GetByte(a)
+	v := emu.Read(a)
 	bit7 := (v & 0b10000000) // Masks v to clear everything but bit 7
 
 	// Bit 7 is added to the result so it remains unchanged
 	result := v>>1 | bit7
 
-	emu.RAM.SetByte(result, a)
+	emu.Write(result, a)
+	emu.CPU.SetFlag(result == 0, cpu.FlagZ)
+	emu.CPU.SetFlag(false, cpu.FlagN)
+	emu.CPU.SetFlag(false, cpu.FlagH)
 	// If bit 0 was 1, we set flag C
 	emu.CPU.SetFlag(v<<7 > 0, cpu.FlagC)
 	emu.CPU.PC++
+	return 4
 }
 
 // SWAP r: Swap nibbles (register)
 //
 // Swaps the high nibble of a register
 // with its low nibble.
-func SWAPr(r cpu.Halve, emu emulator.Emulation) {
+func SWAPr(r cpu.Halve, emu emulator.Emulation) int {
 	v := emu.CPU.GetHalve(r)
 	// Stores the high nibble
 	hNib := v & 0xF0
@@ -1446,15 +1593,16 @@ func SWAPr(r cpu.Halve, emu emulator.Emulation) {
 	emu.CPU.SetFlag(false, cpu.FlagH)
 	emu.CPU.SetFlag(false, cpu.FlagC)
 	emu.CPU.PC++
+	return 2
 }
 
 // SWAP HL: Swap nibbles (indirect HL)
 //
 // Swaps the high nibble of the memory
 // value in HL with its low nibble.
-func SWAPHL(emu emulator.Emulation) {
+func SWAPHL(emu emulator.Emulation) int {
 	a := emu.CPU.GetReg(cpu.HL)
-	v := emu.RAM.GetByte(a)
+	v := emu.Read(a)
 	// Stores the high nibble
 	hNib := v & 0xF0
 
@@ -1464,13 +1612,14 @@ func SWAPHL(emu emulator.Emulation) {
 	// right to perform the swap
 	result := v<<4 | (hNib >> 4)
 
-	emu.RAM.SetByte(result, a)
+	emu.Write(result, a)
 
 	emu.CPU.SetFlag(result == 0, cpu.FlagZ)
 	emu.CPU.SetFlag(false, cpu.FlagN)
 	emu.CPU.SetFlag(false, cpu.FlagH)
 	emu.CPU.SetFlag(false, cpu.FlagC)
 	emu.CPU.PC++
+	return 4
 }
 
 // SRL r: Shift right logical (register)
@@ -1480,14 +1629,18 @@ func SWAPHL(emu emulator.Emulation) {
 //
 // NOTE: Bit 7 is kept as-is. Its value *will* be
 // rotated right, but bit 7 will remain unchanged.
-func SRLr(r cpu.Halve, emu emulator.Emulation) {
+func SRLr(r cpu.Halve, emu emulator.Emulation) int {
 	v := emu.CPU.GetHalve(r)
 	result := v >> 1
 
 	emu.CPU.SetHalve(r, result)
+	emu.CPU.SetFlag(result == 0, cpu.FlagZ)
+	emu.CPU.SetFlag(false, cpu.FlagN)
+	emu.CPU.SetFlag(false, cpu.FlagH)
 	// If bit 0 was 1, we set flag C
 	emu.CPU.SetFlag(v<<7 > 0, cpu.FlagC)
 	emu.CPU.PC++
+	return 2
 }
 
 // SRL (HL): Shift right logical (indirect HL)
@@ -1497,52 +1650,58 @@ func SRLr(r cpu.Halve, emu emulator.Emulation) {
 //
 // NOTE: Bit 7 is kept as-is. Its value *will* be
 // rotated right, but bit 7 will remain unchanged.
-func SRLHL(emu emulator.Emulation) {
+func SRLHL(emu emulator.Emulation) int {
 	a := emu.CPU.GetReg(cpu.HL)
-	v := emu.RAM.GetByte(a)
+	v := emu.Read(a)
 
 	result := v >> 1
 
-	emu.RAM.SetByte(result, a)
+	emu.Write(result, a)
+	emu.CPU.SetFlag(result == 0, cpu.FlagZ)
+	emu.CPU.SetFlag(false, cpu.FlagN)
+	emu.CPU.SetFlag(false, cpu.FlagH)
 	// If bit 0 was 1, we set flag C
 	emu.CPU.SetFlag(v<<7 > 0, cpu.FlagC)
 	emu.CPU.PC++
+	return 4
 }
 
 // BIT b, r: Test bit (register)
 //
 // Sets flag Z if the bit in position b of register r is zero.
-func BITbr(b byte, r cpu.Halve, emu emulator.Emulation) {
+func BITbr(b byte, r cpu.Halve, emu emulator.Emulation) int {
 	v := emu.CPU.GetHalve(r)
 	// Filters out everything but bit b
 	bit := v & cpu.GetBitMask(b)
 
-	emu.CPU.SetFlag(bit > 0, cpu.FlagZ)
+	emu.CPU.SetFlag(bit == 0, cpu.FlagZ)
 	emu.CPU.SetFlag(false, cpu.FlagN)
 	emu.CPU.SetFlag(true, cpu.FlagH)
 	emu.CPU.PC++
+	return 2
 }
 
 // BIT b, (HL): Test bit (indirect HL)
 //
 // Sets flag Z if the bit in position b of the
 // memory value in address HL is zero.
-func BITbHL(b byte, emu emulator.Emulation) {
+func BITbHL(b byte, emu emulator.Emulation) int {
 	a := emu.CPU.GetReg(cpu.HL)
-	v := emu.RAM.GetByte(a)
+	v := emu.Read(a)
 	// Filters out everything but bit b
 	bit := v & cpu.GetBitMask(b)
 
-	emu.CPU.SetFlag(bit > 0, cpu.FlagZ)
+	emu.CPU.SetFlag(bit == 0, cpu.FlagZ)
 	emu.CPU.SetFlag(false, cpu.FlagN)
 	emu.CPU.SetFlag(true, cpu.FlagH)
 	emu.CPU.PC++
+	return 3
 }
 
 // RES b, r: Reset bit (register)
 //
 // Resets (sets to 0) the bit in position b in register r.
-func RESbr(b byte, r cpu.Halve, emu emulator.Emulation) {
+func RESbr(b byte, r cpu.Halve, emu emulator.Emulation) int {
 	v := emu.CPU.GetHalve(r)
 	// Inverts the mask to filter out bit b
 	mask := ^cpu.GetBitMask(b)
@@ -1550,47 +1709,51 @@ func RESbr(b byte, r cpu.Halve, emu emulator.Emulation) {
 
 	emu.CPU.SetHalve(r, result)
 	emu.CPU.PC++
+	return 2
 }
 
 // SET b, (HL): Set bit (indirect HL)
 //
 // Sets the bit in position b of the memory
 // value in address HL.
-func RESbHL(b byte, emu emulator.Emulation) {
+func RESbHL(b byte, emu emulator.Emulation) int {
 	a := emu.CPU.GetReg(cpu.HL)
-	v := emu.RAM.GetByte(a)
+	v := emu.Read(a)
 	// Inverts the mask to filter out bit b
 	mask := ^cpu.GetBitMask(b)
 	result := v & mask
 
-	emu.RAM.SetByte(result, a)
+	emu.Write(result, a)
 	emu.CPU.PC++
+	return 4
 }
 
 // SET b, r: Set bit (register)
 //
 // Sets the bit in position b in register r.
-func SETbr(b byte, r cpu.Halve, emu emulator.Emulation) {
+func SETbr(b byte, r cpu.Halve, emu emulator.Emulation) int {
 	v := emu.CPU.GetHalve(r)
 	mask := cpu.GetBitMask(b)
 	result := v | mask
 
 	emu.CPU.SetHalve(r, result)
 	emu.CPU.PC++
+	return 2
 }
 
 // SET b, (HL): Set bit (indirect HL)
 //
 // Sets the bit in position b of the memory
 // value in address HL.
-func SETbHL(b byte, emu emulator.Emulation) {
+func SETbHL(b byte, emu emulator.Emulation) int {
 	a := emu.CPU.GetReg(cpu.HL)
-	v := emu.RAM.GetByte(a)
+	v := emu.Read(a)
 	mask := cpu.GetBitMask(b)
 	result := v | mask
 
-	emu.RAM.SetByte(result, a)
+	emu.Write(result, a)
 	emu.CPU.PC++
+	return 4
 }
 
 // JP nn: Jump
@@ -1598,24 +1761,26 @@ func SETbHL(b byte, emu emulator.Emulation) {
 // Jumps (inconditionally) to the address
 // specified in the next two bytes to the
 // instruction.
-func JPnn(emu emulator.Emulation) {
+func JPnn(emu emulator.Emulation) int {
 	emu.CPU.PC++
-	nLo := emu.RAM.GetByte(emu.CPU.PC)
+	nLo := emu.Read(emu.CPU.PC)
 	emu.CPU.PC++
-	nHi := emu.RAM.GetByte(emu.CPU.PC)
+	nHi := emu.Read(emu.CPU.PC)
 	v := uint16(nHi)<<8 | uint16(nLo)
 
 	emu.CPU.SetReg(cpu.PC, v)
+	return 4
 }
 
 // JP HL: Jump to HL
 //
 // Jumps (inconditionally) to the address
 // specified in register HL
-func JPHL(emu emulator.Emulation) {
+func JPHL(emu emulator.Emulation) int {
 	v := emu.CPU.GetReg(cpu.HL)
 
 	emu.CPU.SetReg(cpu.PC, v)
+	return 1
 }
 
 // JP cc, nn: Jump (conditional)
@@ -1623,18 +1788,20 @@ func JPHL(emu emulator.Emulation) {
 // Jumps to the address specified in the following
 // two bytes in memory from the instruction if
 // the condition cc is true.
-func JPccnn(cc cpu.CondType, emu emulator.Emulation) {
+func JPccnn(cc cpu.CondType, emu emulator.Emulation) int {
 	emu.CPU.PC++
-	nLo := emu.RAM.GetByte(emu.CPU.PC)
+	nLo := emu.Read(emu.CPU.PC)
 	emu.CPU.PC++
-	nHi := emu.RAM.GetByte(emu.CPU.PC)
+	nHi := emu.Read(emu.CPU.PC)
 	v := uint16(nHi)<<8 | uint16(nLo)
 
 	if cc.ToCondition(*emu.CPU) {
 		emu.CPU.SetReg(cpu.PC, v)
-	} else {
-		emu.CPU.PC++
+		return 4
 	}
+
+	emu.CPU.PC++
+	return 3
 }
 
 // JR e: Relative jump
@@ -1642,13 +1809,14 @@ func JPccnn(cc cpu.CondType, emu emulator.Emulation) {
 // Jumps (inconditionally) to the address calculated
 // by adding the signed value e to the PC value, e
 // being the value in memory next to PC.
-func JRe(emu emulator.Emulation) {
+func JRe(emu emulator.Emulation) int {
 	emu.CPU.PC++
-	e := int8(emu.RAM.GetByte(emu.CPU.PC)) // Signed
+	e := int8(emu.Read(emu.CPU.PC)) // Signed
 	// Casted into int 32 to respect e's signed value
 	v := uint16(emu.CPU.GetReg(cpu.PC)) + uint16(e)
 
 	emu.CPU.SetReg(cpu.PC, uint16(v))
+	return 3
 }
 
 // JR e: Relative jump
@@ -1657,17 +1825,19 @@ func JRe(emu emulator.Emulation) {
 // by adding the signed value e to the PC value, e
 // being the value in memory next to PC, so long as
 // cc is true.
-func JRcce(cc cpu.CondType, emu emulator.Emulation) {
+func JRcce(cc cpu.CondType, emu emulator.Emulation) int {
 	emu.CPU.PC++
-	e := int8(emu.RAM.GetByte(emu.CPU.PC)) // Signed
+	e := int8(emu.Read(emu.CPU.PC)) // Signed
 	// Casted into int 32 to respect e's signed value
 	v := uint16(emu.CPU.GetReg(cpu.PC)) + uint16(e)
 
 	if cc.ToCondition(*emu.CPU) {
 		emu.CPU.SetReg(cpu.PC, uint16(v))
-	} else {
-		emu.CPU.PC++
+		return 3
 	}
+
+	emu.CPU.PC++
+	return 2
 }
 
 // CALL nn: Call function
@@ -1676,19 +1846,19 @@ func JRcce(cc cpu.CondType, emu emulator.Emulation) {
 // in the next two bytes in memory from the instruction,
 // meaning that the the return address will be pushed
 // into the stack and the PC value will change to nn.
-func CALLnn(emu emulator.Emulation) {
+func CALLnn(emu emulator.Emulation) int {
 	emu.CPU.PC++
-	nLo := emu.RAM.GetByte(emu.CPU.PC)
+	nLo := emu.Read(emu.CPU.PC)
 	emu.CPU.PC++
-	nHi := emu.RAM.GetByte(emu.CPU.PC)
+	nHi := emu.Read(emu.CPU.PC)
 	emu.CPU.PC++
 	a := uint16(nHi)<<8 | uint16(nLo)
 
-	emu.CPU.SP--
-	emu.RAM.Set16Bit(emu.CPU.GetReg(cpu.PC), emu.CPU.SP)
-	emu.CPU.SP--
+	emu.Idle()
+	push16(emu.CPU.GetReg(cpu.PC), emu)
 
 	emu.CPU.SetReg(cpu.PC, a)
+	return 6
 }
 
 // CALL cc, nn: Call function (conditional)
@@ -1696,21 +1866,22 @@ func CALLnn(emu emulator.Emulation) {
 // Calls the function in the address specified
 // in the next two bytes in memory from the instruction
 // so long as condition cc is true.
-func CALLccnn(cc cpu.CondType, emu emulator.Emulation) {
+func CALLccnn(cc cpu.CondType, emu emulator.Emulation) int {
 	emu.CPU.PC++
-	nLo := emu.RAM.GetByte(emu.CPU.PC)
+	nLo := emu.Read(emu.CPU.PC)
 	emu.CPU.PC++
-	nHi := emu.RAM.GetByte(emu.CPU.PC)
+	nHi := emu.Read(emu.CPU.PC)
 	emu.CPU.PC++
 	a := uint16(nHi)<<8 | uint16(nLo)
 
 	if cc.ToCondition(*emu.CPU) {
-		emu.CPU.SP--
-		emu.RAM.Set16Bit(emu.CPU.GetReg(cpu.PC), emu.CPU.SP)
-		emu.CPU.SP--
+		emu.Idle()
+		push16(emu.CPU.GetReg(cpu.PC), emu)
 
 		emu.CPU.SetReg(cpu.PC, a)
+		return 6
 	}
+	return 3
 }
 
 // RET: Return from function
@@ -1718,11 +1889,11 @@ func CALLccnn(cc cpu.CondType, emu emulator.Emulation) {
 // Returns from a function inconditionally,
 // changing the value in PC to the address
 // specified by the stack pointer.
-func RET(emu emulator.Emulation) {
-	v := emu.RAM.Get16Bit(emu.CPU.GetReg(cpu.SP) + 1)
-	emu.CPU.SetReg(cpu.SP, emu.CPU.GetReg(cpu.SP)+2)
+func RET(emu emulator.Emulation) int {
+	v := pop16(emu)
 
 	emu.CPU.SetReg(cpu.PC, v)
+	return 4
 }
 
 // RET cc: Return from function (conditional)
@@ -1730,41 +1901,105 @@ func RET(emu emulator.Emulation) {
 // Returns from a function if cc is true,
 // changing the value in PC to the address
 // specified by the stack pointer.
-func RETcc(cc cpu.CondType, emu emulator.Emulation) {
+func RETcc(cc cpu.CondType, emu emulator.Emulation) int {
+	// The condition is checked on its own M-cycle
+	emu.Idle()
 	if cc.ToCondition(*emu.CPU) {
-		v := emu.RAM.Get16Bit(emu.CPU.GetReg(cpu.SP) + 1)
-		emu.CPU.SetReg(cpu.SP, emu.CPU.GetReg(cpu.SP)+2)
+		v := pop16(emu)
 
 		emu.CPU.SetReg(cpu.PC, v)
-	} else {
-		emu.CPU.PC++
+		return 5
 	}
 
+	emu.CPU.PC++
+	return 2
 }
 
 // RETI: Return from interrupt handler
 //
 // Unconditional return + enables interrupts
-func RETI(emu emulator.Emulation) {
-	v := emu.RAM.Get16Bit(emu.CPU.GetReg(cpu.SP) + 1)
-	emu.CPU.SetReg(cpu.SP, emu.CPU.GetReg(cpu.SP)+2)
+func RETI(emu emulator.Emulation) int {
+	v := pop16(emu)
 
 	emu.CPU.SetReg(cpu.PC, v)
 	emu.CPU.IME = true
+	return 4
 }
 
 // RST n: Restart / Call function (implied)
 //
-// Calls the 8-bit address in the next memory
-// value to the instruction
-func RSTn(emu emulator.Emulation) {
+// Calls the address n, which is encoded in the
+// opcode itself (0x00, 0x08, 0x10 ... 0x38).
+func RSTn(n byte, emu emulator.Emulation) int {
 	emu.CPU.PC++
-	v := uint16(emu.RAM.GetByte(emu.CPU.GetReg(cpu.PC)))
+	v := uint16(n)
 
-	emu.CPU.SP--
-	emu.RAM.Set16Bit(emu.CPU.GetReg(cpu.PC), emu.CPU.SP)
-	emu.CPU.SP--
+	emu.Idle()
+	push16(emu.CPU.GetReg(cpu.PC), emu)
 
 	emu.CPU.SetReg(cpu.PC, v)
+	return 4
+}
+
+// NOP: No operation
+//
+// Does nothing apart from advancing PC.
+func NOP(emu emulator.Emulation) int {
+	emu.CPU.PC++
+	return 1
+}
+
+// HALT: Halt system clock
+//
+// Halts the CPU until an interrupt is pending.
+//
+// NOTE: If IME is not set and an interrupt is already
+// pending, the CPU does not halt. Instead, the byte after
+// HALT is read twice because PC fails to increment (the
+// so-called HALT bug).
+func HALT(emu emulator.Emulation) int {
+	emu.CPU.PC++
+	if !emu.CPU.IME && emu.Interrupts.Pending() {
+		emu.CPU.HaltBug = true
+	} else {
+		emu.CPU.Halted = true
+	}
+	return 1
+}
+
+// STOP: Stop system and main clocks
+//
+// Enters low power mode until a joypad input
+// line goes low. In CGB mode, if a speed switch
+// was armed through KEY1, the speed switches
+// instead (see Emulation.SwitchSpeed).
+//
+// NOTE: STOP is 2 bytes long, the second one
+// being ignored.
+func STOP(emu emulator.Emulation) int {
+	emu.CPU.PC += 2
+	if !emu.SwitchSpeed() {
+		emu.CPU.Stopped = true
+	}
+	return 1
+}
 
+// DI: Disable interrupts
+//
+// Resets IME, also cancelling any pending EI.
+func DI(emu emulator.Emulation) int {
+	emu.CPU.IME = false
+	emu.CPU.IMEScheduled = false
+	emu.CPU.PC++
+	return 1
+}
+
+// EI: Enable interrupts
+//
+// Sets IME after the instruction that
+// follows EI has been executed.
+func EI(emu emulator.Emulation) int {
+	emu.CPU.IMEScheduled = true
+	emu.CPU.PC++
+	return 1
 }
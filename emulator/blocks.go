@@ -0,0 +1,221 @@
+package emulator
+
+import "github.com/markelmencia/gogb/bus"
+
+// Defines how instructions are fetched and decoded.
+type Engine byte
+
+// Defines an enum with each execution engine.
+const (
+	// Fetches and decodes every instruction from
+	// memory right before executing it.
+	EngineInterpreter Engine = iota
+	// Decodes straight-line runs of code (basic blocks)
+	// once and caches them, so that executing them again
+	// skips the decoding. Produces the same results as
+	// EngineInterpreter.
+	EngineCachedBlocks
+	// Runs EngineCachedBlocks and, alongside it, a copy of
+	// the emulation with EngineInterpreter. Step returns
+	// the first step both disagree on as a *DivergenceError,
+	// whatever the fault policy is. Registers, M-cycles,
+	// faults and the bytes written by the CPU are compared.
+	// Meant to debug the block cache, so the emulation must
+	// only be changed through Step, which is more than
+	// twice as slow.
+	EngineDifferential
+)
+
+// Maximum number of instructions in a block.
+const maxBlockLength = 64
+
+// Length in bytes of each opcode in the base instruction
+// set, and whether it may write PC. Like the decode tables,
+// they are filled in by package instructions. Illegal
+// opcodes have a length of 0.
+var (
+	OpcodeLengths  [256]int
+	OpcodeBranches [256]bool
+)
+
+// Represents an instruction decoded ahead of execution.
+type decodedInstruction struct {
+	// Address of the opcode, or of the prefix
+	// for instructions prefixed by 0xCB
+	pc      uint16
+	handler Instruction
+	// Opcode, or second opcode for
+	// instructions prefixed by 0xCB
+	opcode byte
+	cb     bool
+}
+
+// Represents a block of instructions decoded ahead
+// of execution. Blocks are empty if they start with
+// an illegal opcode.
+type block []decodedInstruction
+
+// Caches the blocks decoded by EngineCachedBlocks.
+type blockCache struct {
+	// Blocks indexed by the address they start
+	// at, or nil if not decoded
+	blocks []*block
+	// Addresses of the decoded blocks
+	starts []uint16
+	// True for every address an opcode was decoded from
+	code []bool
+	// Instructions left in the block being executed
+	current []decodedInstruction
+}
+
+// Returns an empty block cache.
+func newBlockCache() *blockCache {
+	return &blockCache{
+		blocks: make([]*block, 0x10000),
+		code:   make([]bool, 0x10000),
+	}
+}
+
+// Returns the instruction at pc, decoding the block that
+// starts at pc if it is not cached. Returns false if pc
+// does not hold a legal instruction.
+func (c *blockCache) fetch(e *Emulation, pc uint16) (decodedInstruction, bool) {
+	if len(c.current) == 0 || c.current[0].pc != pc {
+		b := c.blocks[pc]
+		if b == nil {
+			b = c.decode(e, pc)
+			c.blocks[pc] = b
+			c.starts = append(c.starts, pc)
+		}
+		c.current = *b
+	}
+
+	if len(c.current) == 0 {
+		return decodedInstruction{}, false
+	}
+	in := c.current[0]
+	c.current = c.current[1:]
+	return in, true
+}
+
+// Decodes the block that starts at pc. The block ends
+// after an instruction that may write PC, before an
+// illegal opcode or when it reaches its maximum length.
+func (c *blockCache) decode(e *Emulation, pc uint16) *block {
+	b := block{}
+	a := pc
+	for len(b) < maxBlockLength && executable(a) {
+		op := e.RAM.GetByte(a)
+		in := decodedInstruction{pc: a, handler: Opcodes[op], opcode: op}
+		length := OpcodeLengths[op]
+		if op == 0xCB {
+			in.opcode = e.RAM.GetByte(a + 1)
+			in.handler = CBOpcodes[in.opcode]
+			in.cb = true
+			length = 2
+		}
+
+		if in.handler == nil {
+			break
+		}
+		c.code[a] = true
+		if in.cb {
+			c.code[a+1] = true
+		}
+		b = append(b, in)
+
+		next := a + uint16(length)
+		if OpcodeBranches[op] || next < a {
+			break
+		}
+		a = next
+	}
+	return &b
+}
+
+// Invalidates the cache if a, or the address it is
+// mirrored at, holds a decoded opcode.
+func (c *blockCache) written(a uint16) {
+	if c.code[a] || c.code[echoAlias(a)] {
+		c.flush()
+	}
+}
+
+// Returns the address of echo RAM that mirrors WRAM
+// address a, or the other way around. Other addresses
+// are returned as is.
+func echoAlias(a uint16) uint16 {
+	const size = bus.OAMStart - bus.EchoStart
+	switch {
+	case a >= bus.WRAMStart && a < bus.WRAMStart+size:
+		return a + (bus.EchoStart - bus.WRAMStart)
+	case a >= bus.EchoStart && a < bus.OAMStart:
+		return a - (bus.EchoStart - bus.WRAMStart)
+	}
+	return a
+}
+
+// Drops every cached block.
+func (c *blockCache) flush() {
+	for _, a := range c.starts {
+		c.blocks[a] = nil
+	}
+	c.starts = c.starts[:0]
+	clear(c.code)
+	c.current = nil
+}
+
+// Drops every block cached by EngineCachedBlocks. Writes
+// made by the CPU (bank switches included) and boot ROM
+// unmapping invalidate the cache on their own, but it must
+// be invalidated after modifying memory from outside the
+// emulation (eg. through Emulation.RAM or Emulation.MBC).
+func (e Emulation) InvalidateBlocks() {
+	if e.blocks != nil {
+		e.blocks.flush()
+	}
+}
+
+// Steps the interpreter emulation of EngineDifferential,
+// comparing it with e, which has just stepped taking m
+// M-cycles and returning err. The divergence is returned
+// regardless of the fault policy, as it is a bug in the
+// block cache rather than in the emulated program. Only
+// the first one is reported, as from then on both
+// emulations differ.
+func (e *Emulation) compareShadow(m int, err error) (int, error) {
+	pc := e.shadow.CPU.PC
+	sm, serr := e.shadow.step()
+
+	div := &DivergenceError{PC: pc, Interpreter: *e.shadow.CPU, Cached: *e.CPU}
+	diverged := sm != m || (serr == nil) != (err == nil) || div.Interpreter != div.Cached ||
+		e.shadow.Clock.Cycles != e.Clock.Cycles
+	if !diverged {
+		div.Address, diverged = e.shadow.compareWrites(e)
+	}
+	*e.writes = (*e.writes)[:0]
+	*e.shadow.writes = (*e.shadow.writes)[:0]
+	if !diverged {
+		return m, err
+	}
+
+	e.shadow = nil
+	if err == nil {
+		err = div
+	}
+	return m, err
+}
+
+// Returns the first address written in the last step by
+// either s or e that holds a different value in each,
+// and true if there is one.
+func (s *Emulation) compareWrites(e *Emulation) (uint16, bool) {
+	for _, writes := range []*[]uint16{s.writes, e.writes} {
+		for _, a := range *writes {
+			if s.RAM.GetByte(a) != e.RAM.GetByte(a) {
+				return a, true
+			}
+		}
+	}
+	return 0, false
+}
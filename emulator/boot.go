@@ -0,0 +1,79 @@
+package emulator
+
+import (
+	"fmt"
+
+	"github.com/markelmencia/gogb/bus"
+)
+
+// Sizes of the boot ROMs of each hardware family.
+const (
+	DMGBootROMSize = 256  // 0x0000-0x00FF
+	CGBBootROMSize = 2304 // 0x0000-0x00FF and 0x0200-0x08FF
+)
+
+// Represents a boot ROM overlaid on top of the
+// start of the cartridge ROM.
+type bootROM struct {
+	data []byte
+	// False once the boot ROM has been unmapped
+	mapped bool
+}
+
+// Returns an error if the boot ROM b can
+// not be run by the emulated model.
+func (e Emulation) checkBootROM(b []byte) error {
+	size := DMGBootROMSize
+	if e.Model.IsColor() {
+		size = CGBBootROMSize
+	}
+
+	if len(b) != size {
+		return fmt.Errorf("Boot ROM has an unexpected size for %s (%d bytes - Expected size: %d bytes)",
+			e.Model, len(b), size,
+		)
+	}
+	return nil
+}
+
+// Returns true if address a is covered by the
+// boot ROM while it is mapped.
+func (b *bootROM) covers(a uint16) bool {
+	if b == nil || !b.mapped || int(a) >= len(b.data) {
+		return false
+	}
+	// The CGB boot ROM leaves a gap for the cartridge header
+	return a < 0x0100 || a >= 0x0200
+}
+
+// Returns true if the boot ROM is mapped into memory.
+// Once it is unmapped it can not be mapped again.
+func (e Emulation) BootROMMapped() bool {
+	return e.boot != nil && e.boot.mapped
+}
+
+// Unmaps the boot ROM if v, written into
+// BOOT (0xFF50), is not 0.
+func (e Emulation) unmapBoot(v byte) {
+	if v == 0 || !e.BootROMMapped() {
+		return
+	}
+	e.boot.mapped = false
+	e.InvalidateBlocks()
+}
+
+// Represents the cartridge region with the
+// boot ROM overlaid on top of it.
+type bootOverlay struct {
+	boot *bootROM
+	bus.Region
+}
+
+// Returns the byte at address a, from the boot ROM
+// if it covers a or from the cartridge otherwise.
+func (o bootOverlay) GetByte(a uint16) byte {
+	if o.boot.covers(a) {
+		return o.boot.data[a]
+	}
+	return o.Region.GetByte(a)
+}
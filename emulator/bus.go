@@ -0,0 +1,68 @@
+package emulator
+
+import "github.com/markelmencia/gogb/bus"
+
+// CPU memory accesses
+//
+// Every access the CPU makes to the bus takes one M-cycle,
+// during which the rest of the machine keeps running. The
+// clock is ticked before the access happens, so the value
+// read or written is the one at the end of the M-cycle.
+
+// Reads the byte at address a, spending one M-cycle.
+//
+// While OAM DMA is occupying the bus a is accessed
+// through, the value on the bus is read instead.
+func (e Emulation) Read(a uint16) byte {
+	e.Clock.Tick()
+	if e.dma.conflicts(a) {
+		if busOf(a) == oamBus {
+			return 0xFF
+		}
+		return e.dma.value
+	}
+	return e.RAM.GetByte(a)
+}
+
+// Writes v into address a, spending one M-cycle.
+//
+// While OAM DMA is occupying the bus a is accessed
+// through, the write is lost.
+func (e Emulation) Write(v byte, a uint16) {
+	e.Clock.Tick()
+	if e.dma.conflicts(a) {
+		return
+	}
+	if e.blocks != nil {
+		e.blocks.written(a)
+	}
+	if e.writes != nil {
+		*e.writes = append(*e.writes, a)
+	}
+	// Writes into ROM may switch the mapped banks
+	if e.MBC != nil && a < bus.VRAMStart {
+		e.InvalidateBlocks()
+	}
+	e.RAM.SetByte(v, a)
+}
+
+// Reads the 16-bit value stored in a and a + 1, low
+// byte first. Spends one M-cycle per byte.
+func (e Emulation) Read16(a uint16) uint16 {
+	lo := e.Read(a)
+	hi := e.Read(a + 1)
+	return uint16(hi)<<8 | uint16(lo)
+}
+
+// Writes v into a and a + 1, low byte first.
+// Spends one M-cycle per byte.
+func (e Emulation) Write16(v uint16, a uint16) {
+	e.Write(byte(v), a)
+	e.Write(byte(v>>8), a+1)
+}
+
+// Spends one M-cycle without accessing the bus, for
+// the internal operations of an instruction.
+func (e Emulation) Idle() {
+	e.Clock.Tick()
+}
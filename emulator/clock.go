@@ -0,0 +1,81 @@
+package emulator
+
+// Number of T-cycles (ticks of the 4.19 MHz
+// system clock) that make up one M-cycle.
+const TCyclesPerMCycle = 4
+
+// Defines a part of the machine that is
+// driven by the clock, such as a timer.
+type Component interface {
+	// Advances the component by one M-cycle.
+	Tick()
+}
+
+// Keeps track of the time elapsed in an emulation
+// and drives the components attached to it.
+//
+// Components run in one of two clock domains. Those in
+// the CPU domain (timers, DMA, serial) run at the speed
+// of the CPU, which doubles in CGB double speed mode.
+// Those in the dot domain (PPU, APU) always run at
+// 4.19 MHz, so in double speed mode they are only
+// ticked every other M-cycle.
+type Clock struct {
+	// T-cycles elapsed since the emulation started,
+	// counted at the speed of the CPU. It only ever
+	// increases.
+	Cycles uint64
+	// Ticks of the 4.19 MHz dot clock elapsed since
+	// the emulation started. Unlike Cycles, it does
+	// not speed up in double speed mode.
+	Dots uint64
+	// True if the CPU runs at double speed (CGB only)
+	DoubleSpeed bool
+
+	components    []Component
+	dotComponents []Component
+}
+
+// Attaches c to the CPU domain of the clock, so
+// that it is ticked on every M-cycle from now on.
+func (c *Clock) Attach(comp Component) {
+	c.components = append(c.components, comp)
+}
+
+// Attaches c to the dot domain of the clock, so
+// that it is ticked every 4 dots from now on,
+// regardless of the speed of the CPU.
+func (c *Clock) AttachDots(comp Component) {
+	c.dotComponents = append(c.dotComponents, comp)
+}
+
+// Lets one M-cycle go by, ticking every
+// component attached to the clock.
+func (c *Clock) Tick() {
+	if c == nil {
+		return
+	}
+	c.Cycles += TCyclesPerMCycle
+	for _, comp := range c.components {
+		comp.Tick()
+	}
+
+	if c.DoubleSpeed {
+		c.Dots += TCyclesPerMCycle / 2
+	} else {
+		c.Dots += TCyclesPerMCycle
+	}
+	if c.Dots%TCyclesPerMCycle != 0 {
+		return
+	}
+	for _, comp := range c.dotComponents {
+		comp.Tick()
+	}
+}
+
+// Advances the clock by m M-cycles.
+func (c *Clock) Advance(m int) {
+	for range m {
+		c.Tick()
+	}
+}
@@ -0,0 +1,116 @@
+package emulator
+
+// Number of bytes an OAM DMA transfer copies,
+// one per M-cycle.
+const dmaLength = 0xA0
+
+// Defines the buses memory is accessed through. OAM DMA
+// occupies the bus it reads from, and the OAM itself.
+type memoryBus byte
+
+// Defines an enum with each bus.
+const (
+	// ROM, cartridge RAM and WRAM
+	externalBus memoryBus = iota
+	// VRAM
+	videoBus
+	// OAM and the unusable area
+	oamBus
+	// I/O registers, HRAM and IE, inside the CPU
+	internalBus
+)
+
+// Returns the bus address a is accessed through.
+func busOf(a uint16) memoryBus {
+	switch {
+	case a >= 0xFF00:
+		return internalBus
+	case a >= 0xFE00:
+		return oamBus
+	case a >= 0x8000 && a < 0xA000:
+		return videoBus
+	}
+	return externalBus
+}
+
+// Represents the OAM DMA controller, which copies
+// 160 bytes from XX00-XX9F into OAM, XX being the
+// value written into 0xFF46.
+//
+// The transfer starts one M-cycle after the write and
+// takes 160 M-cycles. Meanwhile, the CPU can not reach
+// OAM, nor the bus the transfer reads from: reads from
+// it return the byte being transferred and writes to it
+// are lost. That is why games run the DMA routine from
+// HRAM.
+type dma struct {
+	e *Emulation
+	// Address the transfer reads from
+	source uint16
+	// Index of the next byte to transfer
+	index int
+	// M-cycles left before the transfer starts
+	delay int
+	// True while the transfer is going on
+	active bool
+	// True while the transfer is occupying the buses.
+	// Restarting a transfer does not free them
+	occupying bool
+	// Last byte read by the transfer, which is
+	// what the CPU reads from the busy bus
+	value byte
+}
+
+// Starts a transfer from address v << 8. Writing
+// while a transfer is going on restarts it.
+func (d *dma) start(v byte) {
+	d.source = uint16(v) << 8
+	d.index = 0
+	d.delay = 1
+	d.active = true
+}
+
+// Returns true if the transfer is occupying the buses.
+func (d *dma) blocking() bool {
+	return d != nil && d.occupying
+}
+
+// Returns true if the CPU can not access address a
+// because the transfer is occupying its bus.
+func (d *dma) conflicts(a uint16) bool {
+	if !d.blocking() {
+		return false
+	}
+	b := busOf(a)
+	return b == oamBus || b == busOf(d.source)
+}
+
+// Copies one byte into OAM.
+func (d *dma) Tick() {
+	if !d.active {
+		return
+	}
+
+	if d.delay > 0 {
+		d.delay--
+		return
+	}
+
+	// The CPU regains the buses one M-cycle
+	// after the last byte is copied
+	if d.index == dmaLength {
+		d.active = false
+		d.occupying = false
+		return
+	}
+
+	d.occupying = true
+	d.value = d.e.RAM.GetByte(d.source + uint16(d.index))
+	d.e.RAM.SetByte(d.value, 0xFE00+uint16(d.index))
+	d.index++
+}
+
+// Returns true if an OAM DMA transfer is occupying the buses.
+func (e Emulation) DMAActive() bool {
+	return e.dma.blocking()
+}
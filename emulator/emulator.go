@@ -1,13 +1,364 @@
 package emulator
 
 import (
+	"log"
+
+	"github.com/markelmencia/gogb/bus"
 	"github.com/markelmencia/gogb/cpu"
-	"github.com/markelmencia/gogb/ram"
+	"github.com/markelmencia/gogb/interrupts"
+	"github.com/markelmencia/gogb/ioreg"
+	"github.com/markelmencia/gogb/mbc"
+	"github.com/markelmencia/gogb/model"
 )
 
 // Represents an instance of an emulation
 type Emulation struct {
 	CPU *cpu.CPU
-	RAM *ram.RAM
+	// Memory the CPU accesses through the address bus.
+	// Emulations created with New use a *bus.Map.
+	RAM bus.Bus
 	ROM *[]byte
+
+	// Hardware model being emulated
+	Model model.Model
+	// True if the Game Boy Color hardware runs in CGB
+	// mode rather than in DMG compatibility mode
+	CGBMode bool
+
+	Clock      *Clock
+	Interrupts interrupts.Controller
+	// I/O registers, for the hardware behind them to
+	// access. Nil if RAM is not a *bus.Map.
+	IO *ioreg.File
+	// Memory bank controller of the cartridge.
+	// Nil if it has none (see mbc.New).
+	MBC mbc.MBC
+	// Seed RAM was randomized with at power-on,
+	// or 0 if it was zeroed (see Config.RandomizeRAM)
+	Seed uint64
+
+	boot *bootROM
+	// Nil unless EngineCachedBlocks or
+	// EngineDifferential is used
+	blocks *blockCache
+	// Emulation run by EngineInterpreter alongside
+	// this one with EngineDifferential. Nil otherwise.
+	shadow *Emulation
+	// Addresses written by the CPU in the current step,
+	// for EngineDifferential to compare. Nil otherwise.
+	writes *[]uint16
+	// Nil until a hook is added
+	hooks *hooks
+	dma   *dma
+
+	// What to do when the CPU faults
+	FaultPolicy FaultPolicy
+	// Where faults are logged with FaultLog.
+	// If nil, the standard logger is used.
+	Logger *log.Logger
+}
+
+// Defines an instruction handler with its operands
+// already bound, ready to be executed. It returns
+// the M-cycles the instruction took.
+type Instruction func(Emulation) int
+
+// Decode tables that map each opcode to its handler.
+// Opcodes contains the base instruction set and CBOpcodes
+// the instructions prefixed by 0xCB.
+//
+// They are filled in by package instructions, so it must be
+// imported for Step to work. Opcodes that do not exist on
+// the SM83 are left as nil.
+var (
+	Opcodes   [256]Instruction
+	CBOpcodes [256]Instruction
+)
+
+// Settings an emulation is created with.
+type Config struct {
+	// Hardware model to emulate. Defaults to DMG.
+	Model model.Model
+	// Boot ROM to run before the cartridge. It must be a
+	// DMG boot ROM (256 bytes) or, for Game Boy Color
+	// hardware, a CGB one (2304 bytes). If nil, the
+	// emulation starts at the cartridge entry point.
+	BootROM []byte
+	// How instructions are fetched and decoded.
+	// Defaults to EngineInterpreter.
+	Engine Engine
+	// If true, VRAM, WRAM and HRAM power on filled with
+	// a pattern typical of the model instead of zeroed.
+	RandomizeRAM bool
+	// Seed the power-on pattern is generated from. If 0,
+	// a random one is picked. Either way, it is recorded
+	// in Emulation.Seed to reproduce the run.
+	Seed uint64
+}
+
+// Creates an emulation for the cartridge ROM rom.
+//
+// The ROM is mapped into memory through the memory bank
+// controller of the cartridge (see mbc.New), or its
+// first 32 KiB directly if it has none.
+// If a boot ROM is configured, it is overlaid on top of
+// it and the CPU starts running it from 0x0000, until
+// it unmaps itself by writing to 0xFF50. Otherwise,
+// the machine is left in the state the boot ROM of the
+// configured model leaves it with (see PostBoot), ready to
+// start from the cartridge entry point.
+func New(rom []byte, cfg Config) (*Emulation, error) {
+	// Both emulations must start from the same RAM
+	var shadow *Emulation
+	if cfg.Engine == EngineDifferential {
+		if cfg.RandomizeRAM && cfg.Seed == 0 {
+			cfg.Seed = randomSeed()
+		}
+		shadowCfg := cfg
+		shadowCfg.Engine = EngineInterpreter
+		var err error
+		if shadow, err = New(rom, shadowCfg); err != nil {
+			return nil, err
+		}
+	}
+
+	m := bus.NewMap(rom, cfg.Model)
+
+	e := &Emulation{
+		CPU:        &cpu.CPU{},
+		RAM:        m,
+		ROM:        &rom,
+		Model:      cfg.Model,
+		Clock:      &Clock{},
+		Interrupts: interrupts.Controller{Memory: m},
+	}
+	if e.MBC = mbc.New(rom); e.MBC != nil {
+		m.Cartridge, m.ExternalRAM = e.MBC, e.MBC
+	}
+	if c, ok := e.MBC.(*mbc.MBC5); ok && c.Rumble() {
+		c.OnRumble = e.reportRumble
+	}
+	e.CGBMode = e.Model.IsColor() && e.cgbCartridge()
+	e.IO = ioreg.NewFile(e.Model, e.CGBMode)
+	// No joypad is attached, so no button is ever pressed
+	// and every input line stays high
+	e.IO.Set(0x0F, ioreg.P1)
+	m.IO = ioRegion{e: e, m: m, File: e.IO}
+	e.dma = &dma{e: e}
+	e.Clock.Attach(e.dma)
+	if cfg.Engine == EngineCachedBlocks || cfg.Engine == EngineDifferential {
+		e.blocks = newBlockCache()
+	}
+	if shadow != nil {
+		e.shadow = shadow
+		e.writes = &[]uint16{}
+		shadow.writes = &[]uint16{}
+	}
+	if cfg.RandomizeRAM {
+		e.Seed = cfg.Seed
+		if e.Seed == 0 {
+			e.Seed = randomSeed()
+		}
+		e.randomizeRAM(m, e.Seed)
+	}
+
+	if cfg.BootROM == nil {
+		e.PostBoot()
+		return e, nil
+	}
+
+	if err := e.checkBootROM(cfg.BootROM); err != nil {
+		return nil, err
+	}
+	e.boot = &bootROM{data: cfg.BootROM, mapped: true}
+	m.Cartridge = bootOverlay{boot: e.boot, Region: m.Cartridge}
+	return e, nil
+}
+
+// Returns true if any joypad input line is low, which
+// is what brings the CPU out of STOP. Lines are only
+// pulled low by setting P1 with IO.Set.
+func (e Emulation) joypadLineLow() bool {
+	return e.register(ioreg.P1)&0x0F != 0x0F
+}
+
+// Fetches the opcode pointed by PC, decodes it (or takes
+// it from the block cache, see Engine) and executes
+// exactly one instruction. The clock is advanced
+// by the cost of the instruction, which is also returned
+// in M-cycles.
+//
+// Execution is M-cycle accurate: every memory access
+// happens on its own M-cycle, with the rest of the machine
+// running in between (see Emulation.Read). Internal
+// M-cycles that instructions spend after their last
+// memory access are spent here.
+//
+// If IME is set and an interrupt is pending, the interrupt
+// is serviced instead of executing an instruction.
+//
+// While the CPU is halted, stopped, switching speeds
+// or locked up no instruction is executed, and Step
+// only lets one M-cycle go by.
+//
+// Faults are handled according to the fault policy
+// (see FaultPolicy). Errors are always one of
+// *IllegalOpcodeError, *StackWraparoundError,
+// *UnmappedExecutionError or, with EngineDifferential,
+// *DivergenceError, which is returned whatever the
+// fault policy is.
+func (e *Emulation) Step() (int, error) {
+	m, err := e.step()
+	if e.shadow != nil {
+		return e.compareShadow(m, err)
+	}
+	return m, err
+}
+
+// Executes one step of the emulation (see Step).
+func (e *Emulation) step() (int, error) {
+	if e.CPU.Locked {
+		e.Clock.Tick()
+		return 1, nil
+	}
+
+	if e.CPU.SpeedSwitch > 0 {
+		e.CPU.SpeedSwitch--
+		e.Clock.Tick()
+		return 1, nil
+	}
+
+	if e.CPU.Stopped {
+		if !e.joypadLineLow() {
+			e.Clock.Tick()
+			return 1, nil
+		}
+		e.CPU.Stopped = false
+	}
+
+	if e.CPU.Halted {
+		if !e.Interrupts.Pending() {
+			e.Clock.Tick()
+			return 1, nil
+		}
+		e.CPU.Halted = false
+	}
+
+	pc := e.CPU.PC
+	sp := e.CPU.SP
+	start := e.Clock.Cycles
+
+	if e.CPU.IME {
+		if i, ok := e.Interrupts.Next(); ok {
+			m := e.serviceInterrupt(i)
+			e.settle(start, m)
+			if e.hooks != nil {
+				e.hooks.interrupt(InterruptEvent{Interrupt: i, PC: pc, CPU: *e.CPU, Cycles: m})
+			}
+			return m, e.checkStack(sp, pc)
+		}
+	}
+
+	if !executable(pc) {
+		if err := e.fault(&UnmappedExecutionError{PC: pc}); err != nil {
+			return 0, err
+		}
+	}
+
+	// EI takes effect after the instruction that follows it,
+	// so only a request made before this step is applied
+	enableIME := e.CPU.IMEScheduled
+
+	// With the HALT bug, PC is not incremented after
+	// the fetch, so the same byte is read again
+	haltBug := e.CPU.HaltBug
+
+	// Registers before the fetch, for the hooks
+	var before cpu.CPU
+	if e.hooks != nil {
+		before = *e.CPU
+	}
+
+	var handler Instruction
+	var op byte
+	var cb bool
+	if in, ok := e.fetchCached(pc, haltBug); ok {
+		handler, op, cb = in.handler, in.opcode, in.cb
+	} else {
+		op = e.Read(pc)
+		table := &Opcodes
+
+		if op == 0xCB {
+			// The CB handlers only account for the second byte
+			// of the instruction, so the prefix is skipped here
+			if !haltBug {
+				e.CPU.PC++
+			}
+			op = e.Read(e.CPU.PC)
+			table = &CBOpcodes
+			cb = true
+		}
+
+		handler = table[op]
+		if handler == nil {
+			return e.illegalOpcode(op, pc)
+		}
+	}
+
+	e.CPU.HaltBug = false
+	if haltBug && !cb {
+		// Handlers expect PC to point to the opcode, so
+		// moving it back makes them read it a second time
+		e.CPU.PC--
+	}
+
+	if e.hooks != nil {
+		e.hooks.beforeInstruction(InstructionEvent{PC: pc, Opcode: op, CB: cb, CPU: before})
+	}
+
+	m := handler(*e)
+
+	if enableIME && e.CPU.IMEScheduled {
+		e.CPU.IME = true
+		e.CPU.IMEScheduled = false
+	}
+
+	e.settle(start, m)
+	if e.hooks != nil {
+		e.hooks.afterInstruction(InstructionEvent{PC: pc, Opcode: op, CB: cb, CPU: *e.CPU, Cycles: m})
+	}
+	return m, e.checkStack(sp, pc)
+}
+
+// Fetches the instruction at pc from the block cache,
+// spending the same M-cycles as reading it from memory.
+// Returns false if the instruction must be fetched from
+// memory instead.
+func (e *Emulation) fetchCached(pc uint16, haltBug bool) (decodedInstruction, bool) {
+	// The HALT bug makes the CPU decode bytes that are
+	// not where the cached instructions start, and OAM
+	// DMA may make it fetch something else
+	if e.blocks == nil || haltBug || e.dma.blocking() {
+		return decodedInstruction{}, false
+	}
+
+	in, ok := e.blocks.fetch(e, pc)
+	if !ok {
+		return in, false
+	}
+
+	e.Clock.Tick()
+	if in.cb {
+		e.CPU.PC++
+		e.Clock.Tick()
+	}
+	return in, true
+}
+
+// Spends the M-cycles of an instruction that did not
+// go by during its memory accesses, so that the clock
+// advances exactly m M-cycles since start.
+func (e *Emulation) settle(start uint64, m int) {
+	spent := int((e.Clock.Cycles - start) / TCyclesPerMCycle)
+	e.Clock.Advance(m - spent)
 }
@@ -0,0 +1,145 @@
+package emulator
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/markelmencia/gogb/cpu"
+)
+
+// Defines what an emulation does when the CPU faults.
+type FaultPolicy byte
+
+// Defines an enum with each fault policy.
+const (
+	// Behaves like hardware: illegal opcodes lock up the CPU
+	// until the emulation is reset, and any other fault is
+	// silently ignored. Step never returns an error.
+	FaultLockUp FaultPolicy = iota
+	// Step returns the fault as an error. Faults detected
+	// before executing an instruction prevent its execution,
+	// so stepping again will keep returning the same error.
+	FaultStop
+	// The fault is logged and the emulation continues.
+	// Illegal opcodes are skipped as if they were NOP.
+	FaultLog
+)
+
+// Returned when the CPU fetches an opcode
+// that does not exist on the SM83.
+type IllegalOpcodeError struct {
+	Opcode byte
+	PC     uint16
+}
+
+func (e *IllegalOpcodeError) Error() string {
+	return fmt.Sprintf("illegal opcode 0x%02X at 0x%04X", e.Opcode, e.PC)
+}
+
+// Returned when the stack pointer wraps around the
+// address space while pushing into or popping from
+// the stack.
+type StackWraparoundError struct {
+	// Stack pointer before and after the wraparound
+	From, To uint16
+	// Address of the instruction that moved the stack pointer
+	PC uint16
+}
+
+func (e *StackWraparoundError) Error() string {
+	return fmt.Sprintf("stack pointer wrapped around from 0x%04X to 0x%04X at 0x%04X",
+		e.From, e.To, e.PC,
+	)
+}
+
+// Returned when PC points to a memory region
+// that can not hold code.
+type UnmappedExecutionError struct {
+	PC uint16
+}
+
+func (e *UnmappedExecutionError) Error() string {
+	return fmt.Sprintf("execution from unmapped memory at 0x%04X", e.PC)
+}
+
+// Returned with EngineDifferential when the block cache
+// and the interpreter disagree on the result of a step.
+type DivergenceError struct {
+	// Address of the step both started from
+	PC uint16
+	// Registers after the step with each engine
+	Interpreter, Cached cpu.CPU
+	// Address written with a different value by each
+	// engine, if the registers and cycles agree
+	Address uint16
+}
+
+func (e *DivergenceError) Error() string {
+	if e.Interpreter == e.Cached {
+		return fmt.Sprintf("block cache diverged from the interpreter at 0x%04X writing 0x%04X",
+			e.PC, e.Address,
+		)
+	}
+	return fmt.Sprintf("block cache diverged from the interpreter at 0x%04X", e.PC)
+}
+
+// Returns true if code can be fetched from address a.
+// The only region that can never hold code is the
+// unusable one between OAM and the I/O registers.
+func executable(a uint16) bool {
+	return a < 0xFEA0 || a > 0xFEFF
+}
+
+// Applies the fault policy to err, a fault that does
+// not lock up the CPU. Returns the error Step must return.
+func (e *Emulation) fault(err error) error {
+	switch e.FaultPolicy {
+	case FaultStop:
+		return err
+	case FaultLog:
+		e.logger().Print(err)
+	}
+	return nil
+}
+
+// Applies the fault policy to an illegal opcode
+// fetched from pc. Returns the values Step must return.
+func (e *Emulation) illegalOpcode(op byte, pc uint16) (int, error) {
+	// The fetch has already taken its M-cycle
+	err := &IllegalOpcodeError{Opcode: op, PC: pc}
+	switch e.FaultPolicy {
+	case FaultStop:
+		return 1, err
+	case FaultLog:
+		e.logger().Print(err)
+		e.CPU.PC++
+	default:
+		e.CPU.Locked = true
+	}
+	return 1, nil
+}
+
+// Returns an error if the stack pointer wrapped around
+// while executing the instruction at pc. from is the
+// value SP had before the instruction.
+func (e *Emulation) checkStack(from, pc uint16) error {
+	to := e.CPU.SP
+	// Pushes and pops move SP by exactly two bytes, so
+	// they only wrap around if SP moved the other way
+	diff := int16(to - from)
+	pushWrapped := diff == -2 && to > from
+	popWrapped := diff == 2 && to < from
+	if !pushWrapped && !popWrapped {
+		return nil
+	}
+
+	return e.fault(&StackWraparoundError{From: from, To: to, PC: pc})
+}
+
+// Returns the logger faults are written to.
+func (e *Emulation) logger() *log.Logger {
+	if e.Logger == nil {
+		return log.Default()
+	}
+	return e.Logger
+}
@@ -0,0 +1,122 @@
+package emulator
+
+import (
+	"github.com/markelmencia/gogb/cpu"
+	"github.com/markelmencia/gogb/interrupts"
+)
+
+// Describes an instruction executed by Step.
+type InstructionEvent struct {
+	// Address the instruction was fetched from
+	PC uint16
+	// Opcode, or second opcode for instructions
+	// prefixed by 0xCB
+	Opcode byte
+	CB     bool
+	// Registers before the instruction was fetched for
+	// BeforeInstruction, and after it was executed
+	// for AfterInstruction
+	CPU cpu.CPU
+	// M-cycles the instruction took. Always 0
+	// for BeforeInstruction
+	Cycles int
+}
+
+// Describes an interrupt dispatched by Step.
+type InterruptEvent struct {
+	Interrupt interrupts.Interrupt
+	// Address execution was interrupted at, which
+	// is pushed into the stack
+	PC uint16
+	// Registers after the dispatch, with PC
+	// pointing to the interrupt vector
+	CPU cpu.CPU
+	// M-cycles the dispatch took
+	Cycles int
+}
+
+// Describes the rumble motor of the cartridge
+// being turned on or off.
+type RumbleEvent struct {
+	On bool
+	// Value of Clock.Cycles when it happened
+	Cycles uint64
+}
+
+// Defines functions Step calls to report what it does.
+// Any of them can be left as nil.
+type Hooks struct {
+	// Called after fetching an instruction and
+	// right before executing it
+	BeforeInstruction func(InstructionEvent)
+	// Called right after executing an instruction
+	AfterInstruction func(InstructionEvent)
+	// Called right after dispatching an interrupt
+	Interrupt func(InterruptEvent)
+	// Called whenever the cartridge turns its rumble
+	// motor on or off, in the order it happens
+	Rumble func(RumbleEvent)
+}
+
+// Stores the hooks added to an emulation.
+type hooks struct {
+	before     []func(InstructionEvent)
+	after      []func(InstructionEvent)
+	interrupts []func(InterruptEvent)
+	rumble     []func(RumbleEvent)
+}
+
+// Adds h to the emulation. Hooks are called in the
+// order they were added, and can not be removed.
+//
+// While no hooks are added, Step does not spend
+// any time reporting events.
+func (e *Emulation) AddHooks(h Hooks) {
+	if e.hooks == nil {
+		e.hooks = &hooks{}
+	}
+	if h.BeforeInstruction != nil {
+		e.hooks.before = append(e.hooks.before, h.BeforeInstruction)
+	}
+	if h.AfterInstruction != nil {
+		e.hooks.after = append(e.hooks.after, h.AfterInstruction)
+	}
+	if h.Interrupt != nil {
+		e.hooks.interrupts = append(e.hooks.interrupts, h.Interrupt)
+	}
+	if h.Rumble != nil {
+		e.hooks.rumble = append(e.hooks.rumble, h.Rumble)
+	}
+}
+
+// Reports ev to every BeforeInstruction hook.
+func (h *hooks) beforeInstruction(ev InstructionEvent) {
+	for _, f := range h.before {
+		f(ev)
+	}
+}
+
+// Reports ev to every AfterInstruction hook.
+func (h *hooks) afterInstruction(ev InstructionEvent) {
+	for _, f := range h.after {
+		f(ev)
+	}
+}
+
+// Reports ev to every Interrupt hook.
+func (h *hooks) interrupt(ev InterruptEvent) {
+	for _, f := range h.interrupts {
+		f(ev)
+	}
+}
+
+// Reports the motor of the cartridge being turned
+// on or off to every Rumble hook.
+func (e *Emulation) reportRumble(on bool) {
+	if e.hooks == nil {
+		return
+	}
+	for _, f := range e.hooks.rumble {
+		f(RumbleEvent{On: on, Cycles: e.Clock.Cycles})
+	}
+}
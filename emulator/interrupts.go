@@ -0,0 +1,24 @@
+package emulator
+
+import "github.com/markelmencia/gogb/interrupts"
+
+// Services interrupt i: IME is reset, the interrupt is
+// acknowledged in IF and its handler is called, pushing
+// PC into the stack like CALL does.
+//
+// Returns the M-cycles the dispatch took: two wait
+// states, one per pushed byte and one to set PC.
+func (e Emulation) serviceInterrupt(i interrupts.Interrupt) int {
+	e.CPU.IME = false
+	e.Interrupts.Acknowledge(i)
+
+	e.Idle()
+	e.Idle()
+	e.CPU.SP--
+	e.Write(byte(e.CPU.PC>>8), e.CPU.SP)
+	e.CPU.SP--
+	e.Write(byte(e.CPU.PC), e.CPU.SP)
+
+	e.CPU.PC = i.Vector()
+	return 5
+}
@@ -0,0 +1,65 @@
+package emulator
+
+import (
+	"github.com/markelmencia/gogb/bus"
+	"github.com/markelmencia/gogb/ioreg"
+)
+
+// Represents the I/O registers region, intercepting
+// writes into the registers that change the state of
+// the emulation itself.
+type ioRegion struct {
+	e *Emulation
+	m *bus.Map
+	*ioreg.File
+}
+
+// Sets v into the register at address a.
+func (r ioRegion) SetByte(v byte, a uint16) {
+	switch a {
+	case ioreg.BOOT:
+		r.e.unmapBoot(v)
+	case ioreg.DMA:
+		r.e.dma.start(v)
+	}
+	r.File.SetByte(v, a)
+
+	// The bank registers only exist in CGB mode
+	if !r.e.CGBMode {
+		return
+	}
+	switch a {
+	case ioreg.VBK:
+		if vram, ok := r.m.VRAM.(*bus.Banked); ok && vram.Bank() != int(v&0x01) {
+			vram.SelectBank(int(v & 0x01))
+			r.e.InvalidateBlocks()
+		}
+	case ioreg.SVBK:
+		if wram, ok := r.m.WRAM.(*bus.WRAM); ok {
+			bank := wram.Bank()
+			wram.SelectBank(int(v & 0x07))
+			if wram.Bank() != bank {
+				r.e.InvalidateBlocks()
+			}
+		}
+	}
+}
+
+// Sets v into every bit of the register at address a,
+// without the side effects of a write.
+func (e Emulation) setRegister(v byte, a uint16) {
+	if e.IO != nil && a >= bus.IOStart && a < bus.HRAMStart {
+		e.IO.Set(v, a)
+		return
+	}
+	e.RAM.SetByte(v, a)
+}
+
+// Returns every bit of the register at address a, as
+// the hardware behind it sees it.
+func (e Emulation) register(a uint16) byte {
+	if e.IO != nil && a >= bus.IOStart && a < bus.HRAMStart {
+		return e.IO.Get(a)
+	}
+	return e.RAM.GetByte(a)
+}
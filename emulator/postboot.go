@@ -0,0 +1,196 @@
+package emulator
+
+import (
+	"github.com/markelmencia/gogb/interrupts"
+	"github.com/markelmencia/gogb/ioreg"
+	"github.com/markelmencia/gogb/model"
+)
+
+// Values of the I/O registers after the DMG boot ROM
+// has finished. Other models override some of them
+// (see ioRegistersByModel).
+var postBootIORegisters = map[uint16]byte{
+	ioreg.P1:          0xCF,
+	ioreg.SB:          0x00,
+	ioreg.SC:          0x7E,
+	ioreg.DIV:         0xAB,
+	ioreg.TIMA:        0x00,
+	ioreg.TMA:         0x00,
+	ioreg.TAC:         0xF8,
+	ioreg.IF:          0xE1,
+	ioreg.NR10:        0x80,
+	ioreg.NR11:        0xBF,
+	ioreg.NR12:        0xF3,
+	ioreg.NR13:        0xFF,
+	ioreg.NR14:        0xBF,
+	ioreg.NR21:        0x3F,
+	ioreg.NR22:        0x00,
+	ioreg.NR23:        0xFF,
+	ioreg.NR24:        0xBF,
+	ioreg.NR30:        0x7F,
+	ioreg.NR31:        0xFF,
+	ioreg.NR32:        0x9F,
+	ioreg.NR33:        0xFF,
+	ioreg.NR34:        0xBF,
+	ioreg.NR41:        0xFF,
+	ioreg.NR42:        0x00,
+	ioreg.NR43:        0x00,
+	ioreg.NR44:        0xBF,
+	ioreg.NR50:        0x77,
+	ioreg.NR51:        0xF3,
+	ioreg.NR52:        0xF1,
+	ioreg.LCDC:        0x91,
+	ioreg.STAT:        0x85,
+	ioreg.SCY:         0x00,
+	ioreg.SCX:         0x00,
+	ioreg.LY:          0x00,
+	ioreg.LYC:         0x00,
+	ioreg.DMA:         0xFF,
+	ioreg.BGP:         0xFC,
+	ioreg.OBP0:        0xFF,
+	ioreg.OBP1:        0xFF,
+	ioreg.WY:          0x00,
+	ioreg.WX:          0x00,
+	interrupts.AddrIE: 0x00,
+}
+
+// I/O register values that differ from the
+// DMG ones in each model.
+var ioRegistersByModel = map[model.Model]map[uint16]byte{
+	model.DMG0: {
+		ioreg.DIV:  0x18,
+		ioreg.STAT: 0x81,
+	},
+	model.SGB: {
+		ioreg.NR52: 0xF0,
+	},
+	model.SGB2: {
+		ioreg.NR52: 0xF0,
+	},
+	model.CGB: cgbIORegisters,
+	model.AGB: cgbIORegisters,
+}
+
+// I/O register values that differ from the
+// DMG ones in Game Boy Color hardware.
+var cgbIORegisters = map[uint16]byte{
+	ioreg.SC:    0x7F,
+	ioreg.DMA:   0x00,
+	ioreg.KEY1:  0x7E,
+	ioreg.VBK:   0xFE,
+	ioreg.HDMA1: 0xFF,
+	ioreg.HDMA2: 0xFF,
+	ioreg.HDMA3: 0xFF,
+	ioreg.HDMA4: 0xFF,
+	ioreg.HDMA5: 0xFF,
+	ioreg.RP:    0x3E,
+	ioreg.SVBK:  0xF8,
+}
+
+// Returns the byte at address a of the cartridge
+// header, or 0 if the ROM is too small to have it.
+func (e Emulation) headerByte(a uint16) byte {
+	if e.ROM == nil || int(a) >= len(*e.ROM) {
+		return 0
+	}
+	return (*e.ROM)[a]
+}
+
+// Returns true if the cartridge header asks
+// for Game Boy Color features.
+func (e Emulation) cgbCartridge() bool {
+	return e.headerByte(0x0143)&0x80 != 0
+}
+
+// Sets the CPU and I/O registers to the values the boot
+// ROM of the emulated model leaves them with before
+// jumping to the cartridge entry point. Some of them
+// depend on the cartridge header, which the boot ROM
+// reads while checking it.
+//
+// (read https://gbdev.io/pandocs/Power_Up_Sequence.html)
+// for more information.
+func (e *Emulation) PostBoot() {
+	e.CPU.AF, e.CPU.BC, e.CPU.DE, e.CPU.HL = e.postBootRegisters()
+	e.CPU.SP = 0xFFFE
+	e.CPU.PC = 0x0100
+
+	for a, v := range postBootIORegisters {
+		e.setRegister(v, a)
+	}
+	for a, v := range ioRegistersByModel[e.Model] {
+		e.setRegister(v, a)
+	}
+}
+
+// Returns the values of AF, BC, DE and HL
+// after the boot ROM of the emulated model.
+func (e Emulation) postBootRegisters() (af, bc, de, hl uint16) {
+	switch e.Model {
+	case model.DMG0:
+		return 0x0100, 0xFF13, 0x00C1, 0x8403
+	case model.DMG, model.MGB:
+		// A tells the DMG (0x01) and the MGB (0xFF) apart
+		a := uint16(0x01)
+		if e.Model == model.MGB {
+			a = 0xFF
+		}
+		// H and C are left set unless the
+		// header checksum happens to be 0
+		f := uint16(0xB0)
+		if e.headerByte(0x014D) == 0 {
+			f = 0x80
+		}
+		return a<<8 | f, 0x0013, 0x00D8, 0x014D
+	case model.SGB:
+		return 0x0100, 0x0014, 0x0000, 0xC060
+	case model.SGB2:
+		return 0xFF00, 0x0014, 0x0000, 0xC060
+	}
+
+	// Game Boy Color hardware. The AGB is told apart by B
+	if e.cgbCartridge() {
+		if e.Model == model.AGB {
+			return 0x1100, 0x0100, 0xFF56, 0x000D
+		}
+		return 0x1180, 0x0000, 0xFF56, 0x000D
+	}
+
+	// DMG compatibility mode: B and HL come from the
+	// title checksum the boot ROM uses to pick a palette
+	b := e.titleChecksum()
+	f := uint16(0x80)
+	if e.Model == model.AGB {
+		// The AGB increments B, setting the flags accordingly
+		f = 0x00
+		if b == 0xFF {
+			f |= 0x80
+		}
+		if b&0x0F == 0x0F {
+			f |= 0x20
+		}
+		b++
+	}
+
+	hl = 0x007C
+	if b == 0x43 || b == 0x58 {
+		hl = 0x991A
+	}
+	return 0x1100 | f, uint16(b) << 8, 0x0008, hl
+}
+
+// Returns the sum of the title bytes if the cartridge
+// was published by Nintendo, or 0 otherwise.
+func (e Emulation) titleChecksum() byte {
+	oldLicensee := e.headerByte(0x014B)
+	newLicensee := [2]byte{e.headerByte(0x0144), e.headerByte(0x0145)}
+	if oldLicensee != 0x01 && (oldLicensee != 0x33 || newLicensee != [2]byte{'0', '1'}) {
+		return 0
+	}
+
+	var sum byte
+	for a := uint16(0x0134); a <= 0x0143; a++ {
+		sum += e.headerByte(a)
+	}
+	return sum
+}
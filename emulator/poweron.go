@@ -0,0 +1,65 @@
+package emulator
+
+import (
+	"math/rand/v2"
+
+	"github.com/markelmencia/gogb/bus"
+	"github.com/markelmencia/gogb/interrupts"
+)
+
+// Fills VRAM, WRAM and HRAM of m with a pattern
+// typical of the emulated model at power-on,
+// generated from seed. The same seed always
+// generates the same contents.
+//
+// These are approximations: DMG models power on with
+// noise everywhere, while the WRAM of CGB models
+// shows runs of 0x00 and 0xFF with a few bits flipped.
+// IE is left cleared, as it is on hardware.
+func (e *Emulation) randomizeRAM(m *bus.Map, seed uint64) {
+	r := rand.New(rand.NewPCG(seed, seed))
+
+	noise := func() byte {
+		return byte(r.Uint32())
+	}
+	wram := noise
+	if e.Model.IsColor() {
+		wram = stripes(r)
+	}
+
+	for _, fill := range []struct {
+		region bus.Region
+		next   func() byte
+	}{
+		{m.VRAM, noise}, {m.WRAM, wram}, {m.HRAM, noise},
+	} {
+		if f, ok := fill.region.(bus.Filler); ok {
+			f.Fill(fill.next)
+		}
+	}
+	m.HRAM.SetByte(0x00, interrupts.AddrIE)
+}
+
+// Returns a generator of alternating runs of eight 0x00
+// and eight 0xFF bytes, with each bit flipped with a
+// probability of 1/16.
+func stripes(r *rand.Rand) func() byte {
+	i := 0
+	return func() byte {
+		var v byte
+		if i/8%2 == 1 {
+			v = 0xFF
+		}
+		i++
+		return v ^ byte(r.Uint32()&r.Uint32()&r.Uint32()&r.Uint32())
+	}
+}
+
+// Returns a random seed for RAM randomization.
+func randomSeed() uint64 {
+	for {
+		if s := rand.Uint64(); s != 0 {
+			return s
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package emulator
+
+import "context"
+
+// Defines why a run of the emulation stopped.
+type StopReason byte
+
+// Defines an enum with each stop reason.
+const (
+	// The cycle or frame budget was spent
+	StopBudget StopReason = iota
+	// The breakpoint predicate was met
+	StopBreakpoint
+	// Step returned an error (see FaultPolicy)
+	StopFault
+	// The context was cancelled
+	StopCancelled
+)
+
+var stopReasonToString = map[StopReason]string{
+	StopBudget:     "budget reached",
+	StopBreakpoint: "breakpoint",
+	StopFault:      "fault",
+	StopCancelled:  "cancelled",
+}
+
+// Returns a description of the stop reason.
+func (r StopReason) String() string {
+	return stopReasonToString[r]
+}
+
+// Units of emulated time.
+const (
+	// M-cycles in one second at normal speed.
+	// Double speed mode runs twice as many.
+	MCyclesPerSecond = 1 << 20
+	// Dots (ticks of the 4.19 MHz dot clock) in a
+	// frame: 154 lines of 456 dots each.
+	DotsPerFrame = 154 * 456
+)
+
+// Number of instructions Run executes between
+// checks of its context.
+const cancelCheckInterval = 1024
+
+// Runs the emulation for at least m M-cycles. As
+// instructions are not split, it may run for a few
+// M-cycles more.
+//
+// M-cycles are counted at the speed the CPU runs at, so
+// in CGB double speed mode the same budget covers half
+// the emulated time. RunFrames counts time regardless
+// of the speed.
+//
+// Returns StopBudget once the M-cycles have gone by,
+// or StopFault and the error if Step fails.
+func (e *Emulation) RunFor(m int) (StopReason, error) {
+	end := e.Clock.Cycles + uint64(m)*TCyclesPerMCycle
+	for e.Clock.Cycles < end {
+		if _, err := e.Step(); err != nil {
+			return StopFault, err
+		}
+	}
+	return StopBudget, nil
+}
+
+// Runs the emulation for at least n frames. Frames are
+// measured in dots, so they last the same emulated time
+// regardless of the speed of the CPU.
+//
+// Returns StopBudget once the frames have gone by, or
+// StopFault and the error if Step fails.
+func (e *Emulation) RunFrames(n int) (StopReason, error) {
+	end := e.Clock.Dots + uint64(n)*DotsPerFrame
+	for e.Clock.Dots < end {
+		if _, err := e.Step(); err != nil {
+			return StopFault, err
+		}
+	}
+	return StopBudget, nil
+}
+
+// Runs the emulation until pred returns true. pred is
+// checked before every step, so the emulation does not
+// run at all if it is already met.
+//
+// Returns StopBreakpoint once pred is met, or StopFault
+// and the error if Step fails.
+func (e *Emulation) RunUntil(pred func(*Emulation) bool) (StopReason, error) {
+	for !pred(e) {
+		if _, err := e.Step(); err != nil {
+			return StopFault, err
+		}
+	}
+	return StopBreakpoint, nil
+}
+
+// Runs the emulation until ctx is cancelled, which can
+// be done from another goroutine.
+//
+// Returns StopCancelled and the error of the context once
+// it is cancelled, or StopFault and the error if Step fails.
+func (e *Emulation) Run(ctx context.Context) (StopReason, error) {
+	for i := 0; ; i++ {
+		if i%cancelCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return StopCancelled, err
+			}
+		}
+
+		if _, err := e.Step(); err != nil {
+			return StopFault, err
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package emulator
+
+import "github.com/markelmencia/gogb/ioreg"
+
+// M-cycles the CPU is paused for while
+// switching speeds.
+const speedSwitchMCycles = 2050
+
+// Switches the CPU between normal and double speed if
+// a switch was armed through KEY1, which is what STOP
+// does in CGB mode. Returns true if the speed switched.
+//
+// The CPU then pauses for 2050 M-cycles, during which
+// the rest of the machine keeps running.
+func (e Emulation) SwitchSpeed() bool {
+	if !e.CGBMode || e.register(ioreg.KEY1)&0x01 == 0 {
+		return false
+	}
+
+	// Bit 7 reflects the current speed, and
+	// the switch is no longer armed
+	e.Clock.DoubleSpeed = !e.Clock.DoubleSpeed
+	key1 := byte(0x00)
+	if e.Clock.DoubleSpeed {
+		key1 = 0x80
+	}
+	e.setRegister(key1, ioreg.KEY1)
+	e.CPU.SpeedSwitch = speedSwitchMCycles
+	return true
+}
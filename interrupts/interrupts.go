@@ -0,0 +1,87 @@
+package interrupts
+
+// Defines an interrupt source.
+type Interrupt byte
+
+// Defines an enum with each interrupt source,
+// sorted by priority (VBlank is the highest).
+// Each value is also the bit of the interrupt
+// in registers IF and IE.
+const (
+	VBlank Interrupt = iota
+	LCDStat
+	Timer
+	Serial
+	Joypad
+)
+
+// Addresses of the interrupt registers.
+const (
+	AddrIF uint16 = 0xFF0F // Interrupt Flag
+	AddrIE uint16 = 0xFFFF // Interrupt Enable
+)
+
+// Mask of the IF and IE bits that
+// correspond to an interrupt source.
+const sourcesMask byte = 0x1F
+
+// Returns the mask of the bit that corresponds
+// to the interrupt in registers IF and IE.
+func (i Interrupt) Mask() byte {
+	return 1 << i
+}
+
+// Returns the address of the handler the CPU
+// jumps to when the interrupt is serviced.
+func (i Interrupt) Vector() uint16 {
+	return 0x0040 + uint16(i)*8
+}
+
+// Memory the interrupt registers are mapped into.
+type Memory interface {
+	GetByte(a uint16) byte
+	SetByte(v byte, a uint16)
+}
+
+// Represents the interrupt controller.
+//
+// The controller has no storage of its own: registers
+// IF and IE are kept in memory, at AddrIF and AddrIE,
+// so that programs can access them like hardware.
+type Controller struct {
+	Memory Memory
+}
+
+// Requests interrupt i by setting its bit in IF.
+func (c Controller) Request(i Interrupt) {
+	c.Memory.SetByte(c.Memory.GetByte(AddrIF)|i.Mask(), AddrIF)
+}
+
+// Acknowledges interrupt i by resetting its bit in IF.
+func (c Controller) Acknowledge(i Interrupt) {
+	c.Memory.SetByte(c.Memory.GetByte(AddrIF)&^i.Mask(), AddrIF)
+}
+
+// Returns true if any interrupt has been
+// requested and is enabled in IE.
+func (c Controller) Pending() bool {
+	return c.pendingBits() != 0
+}
+
+// Returns the pending interrupt with the highest priority.
+// If no interrupt is pending, false is returned instead.
+func (c Controller) Next() (Interrupt, bool) {
+	pending := c.pendingBits()
+	for i := VBlank; i <= Joypad; i++ {
+		if pending&i.Mask() != 0 {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// Returns the bits of the interrupts that are
+// both requested and enabled.
+func (c Controller) pendingBits() byte {
+	return c.Memory.GetByte(AddrIE) & c.Memory.GetByte(AddrIF) & sourcesMask
+}
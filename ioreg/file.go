@@ -0,0 +1,81 @@
+package ioreg
+
+import "github.com/markelmencia/gogb/model"
+
+// Number of addresses in the I/O region (0xFF00-0xFF7F).
+const size = 0x80
+
+// Represents the I/O registers of a model as seen from
+// the bus. CPU accesses go through the masks of each
+// register, and unmapped registers read 0xFF and ignore
+// writes. The hardware behind the registers accesses
+// them directly through Get and Set.
+type File struct {
+	values [size]byte
+	// Register mapped at each address, or
+	// nil if the address is unmapped
+	registers [size]*Register
+}
+
+// Returns the I/O registers of model m
+// running in CGB mode or not.
+func NewFile(m model.Model, cgbMode bool) *File {
+	f := &File{}
+	for i := range Registers {
+		r := &Registers[i]
+		if r.Models.On(m, cgbMode) {
+			f.registers[r.Addr-P1] = r
+		}
+	}
+	return f
+}
+
+// Returns the register at address a, or
+// nil if a is not an I/O register.
+func (f *File) register(a uint16) *Register {
+	i := int(a - P1)
+	if a < P1 || i >= size {
+		return nil
+	}
+	return f.registers[i]
+}
+
+// Returns the value the CPU reads from address a.
+func (f *File) GetByte(a uint16) byte {
+	r := f.register(a)
+	if r == nil {
+		return 0xFF
+	}
+	return f.values[a-P1]&r.Read | r.Ones
+}
+
+// Sets v into the writable bits of the
+// register at address a.
+func (f *File) SetByte(v byte, a uint16) {
+	r := f.register(a)
+	if r == nil {
+		return
+	}
+	i := a - P1
+	f.values[i] = f.values[i]&^r.Write | v&r.Write
+}
+
+// Returns every bit of the register at address a, as
+// the hardware behind it sees it. Returns 0xFF if a
+// is not mapped.
+func (f *File) Get(a uint16) byte {
+	if f.register(a) == nil {
+		return 0xFF
+	}
+	return f.values[a-P1]
+}
+
+// Sets v into every bit of the register at address a,
+// as the hardware behind it does. Does nothing if a
+// is not mapped.
+func (f *File) Set(v byte, a uint16) {
+	if f.register(a) == nil {
+		return
+	}
+	f.values[a-P1] = v
+}
@@ -0,0 +1,248 @@
+package ioreg
+
+import (
+	"fmt"
+
+	"github.com/markelmencia/gogb/model"
+)
+
+/* ADDRESSES */
+
+// Addresses of the I/O registers.
+const (
+	// Joypad
+	P1 uint16 = 0xFF00
+
+	// Serial transfer
+	SB uint16 = 0xFF01
+	SC uint16 = 0xFF02
+
+	// Timer
+	DIV  uint16 = 0xFF04
+	TIMA uint16 = 0xFF05
+	TMA  uint16 = 0xFF06
+	TAC  uint16 = 0xFF07
+
+	// Interrupt flags
+	IF uint16 = 0xFF0F
+
+	// Audio
+	NR10 uint16 = 0xFF10
+	NR11 uint16 = 0xFF11
+	NR12 uint16 = 0xFF12
+	NR13 uint16 = 0xFF13
+	NR14 uint16 = 0xFF14
+	NR21 uint16 = 0xFF16
+	NR22 uint16 = 0xFF17
+	NR23 uint16 = 0xFF18
+	NR24 uint16 = 0xFF19
+	NR30 uint16 = 0xFF1A
+	NR31 uint16 = 0xFF1B
+	NR32 uint16 = 0xFF1C
+	NR33 uint16 = 0xFF1D
+	NR34 uint16 = 0xFF1E
+	NR41 uint16 = 0xFF20
+	NR42 uint16 = 0xFF21
+	NR43 uint16 = 0xFF22
+	NR44 uint16 = 0xFF23
+	NR50 uint16 = 0xFF24
+	NR51 uint16 = 0xFF25
+	NR52 uint16 = 0xFF26
+	// First of the 16 bytes of wave RAM
+	WaveRAM uint16 = 0xFF30
+
+	// LCD
+	LCDC uint16 = 0xFF40
+	STAT uint16 = 0xFF41
+	SCY  uint16 = 0xFF42
+	SCX  uint16 = 0xFF43
+	LY   uint16 = 0xFF44
+	LYC  uint16 = 0xFF45
+	DMA  uint16 = 0xFF46
+	BGP  uint16 = 0xFF47
+	OBP0 uint16 = 0xFF48
+	OBP1 uint16 = 0xFF49
+	WY   uint16 = 0xFF4A
+	WX   uint16 = 0xFF4B
+
+	// CGB speed switch
+	KEY1 uint16 = 0xFF4D
+	// CGB VRAM bank
+	VBK uint16 = 0xFF4F
+	// Boot ROM unmapping
+	BOOT uint16 = 0xFF50
+
+	// CGB VRAM DMA
+	HDMA1 uint16 = 0xFF51
+	HDMA2 uint16 = 0xFF52
+	HDMA3 uint16 = 0xFF53
+	HDMA4 uint16 = 0xFF54
+	HDMA5 uint16 = 0xFF55
+
+	// CGB infrared port
+	RP uint16 = 0xFF56
+
+	// CGB palettes
+	BCPS uint16 = 0xFF68
+	BCPD uint16 = 0xFF69
+	OCPS uint16 = 0xFF6A
+	OCPD uint16 = 0xFF6B
+	// CGB object priority mode
+	OPRI uint16 = 0xFF6C
+
+	// CGB WRAM bank
+	SVBK uint16 = 0xFF70
+
+	// CGB undocumented registers
+	FF72 uint16 = 0xFF72
+	FF73 uint16 = 0xFF73
+	FF74 uint16 = 0xFF74
+	FF75 uint16 = 0xFF75
+
+	// CGB audio digital outputs
+	PCM12 uint16 = 0xFF76
+	PCM34 uint16 = 0xFF77
+)
+
+/* REGISTER TABLE */
+
+// Defines the hardware an I/O register exists on.
+type Availability byte
+
+// Defines an enum with each availability.
+const (
+	// Every model, in any mode
+	AllModels Availability = iota
+	// Every model, except Game Boy Color hardware
+	// in CGB mode
+	DMGMode
+	// Game Boy Color hardware in CGB mode
+	CGBMode
+	// Game Boy Color hardware, in any mode
+	CGBHardware
+)
+
+// Returns true if registers with availability a exist
+// on model m running in CGB mode or not.
+func (a Availability) On(m model.Model, cgbMode bool) bool {
+	switch a {
+	case DMGMode:
+		return !cgbMode
+	case CGBMode:
+		return m.IsColor() && cgbMode
+	case CGBHardware:
+		return m.IsColor()
+	}
+	return true
+}
+
+// Describes an I/O register.
+//
+// Bits the CPU can not read, either because they are unused
+// or write-only, always read as 1. Bits the CPU can not write
+// can only be changed by the hardware behind the register.
+type Register struct {
+	Name string
+	Addr uint16
+	// Bits the CPU can read
+	Read byte
+	// Bits the CPU can write
+	Write byte
+	// Bits that always read as 1
+	Ones byte
+	// Hardware the register exists on
+	Models Availability
+}
+
+// Describes every I/O register. Some registers behave
+// differently in CGB mode, so they are described once
+// per mode.
+//
+// (read https://gbdev.io/pandocs/Hardware_Reg_List.html)
+// for more information.
+var Registers = append([]Register{
+	{"P1", P1, 0x3F, 0x30, 0xC0, AllModels},
+	{"SB", SB, 0xFF, 0xFF, 0x00, AllModels},
+	{"SC", SC, 0x81, 0x81, 0x7E, DMGMode},
+	{"SC", SC, 0x83, 0x83, 0x7C, CGBMode},
+	{"DIV", DIV, 0xFF, 0xFF, 0x00, AllModels},
+	{"TIMA", TIMA, 0xFF, 0xFF, 0x00, AllModels},
+	{"TMA", TMA, 0xFF, 0xFF, 0x00, AllModels},
+	{"TAC", TAC, 0x07, 0x07, 0xF8, AllModels},
+	{"IF", IF, 0x1F, 0x1F, 0xE0, AllModels},
+	{"NR10", NR10, 0x7F, 0x7F, 0x80, AllModels},
+	{"NR11", NR11, 0xC0, 0xFF, 0x3F, AllModels},
+	{"NR12", NR12, 0xFF, 0xFF, 0x00, AllModels},
+	{"NR13", NR13, 0x00, 0xFF, 0xFF, AllModels},
+	{"NR14", NR14, 0x40, 0xC7, 0xBF, AllModels},
+	{"NR21", NR21, 0xC0, 0xFF, 0x3F, AllModels},
+	{"NR22", NR22, 0xFF, 0xFF, 0x00, AllModels},
+	{"NR23", NR23, 0x00, 0xFF, 0xFF, AllModels},
+	{"NR24", NR24, 0x40, 0xC7, 0xBF, AllModels},
+	{"NR30", NR30, 0x80, 0x80, 0x7F, AllModels},
+	{"NR31", NR31, 0x00, 0xFF, 0xFF, AllModels},
+	{"NR32", NR32, 0x60, 0x60, 0x9F, AllModels},
+	{"NR33", NR33, 0x00, 0xFF, 0xFF, AllModels},
+	{"NR34", NR34, 0x40, 0xC7, 0xBF, AllModels},
+	{"NR41", NR41, 0x00, 0x3F, 0xFF, AllModels},
+	{"NR42", NR42, 0xFF, 0xFF, 0x00, AllModels},
+	{"NR43", NR43, 0xFF, 0xFF, 0x00, AllModels},
+	{"NR44", NR44, 0x40, 0xC0, 0xBF, AllModels},
+	{"NR50", NR50, 0xFF, 0xFF, 0x00, AllModels},
+	{"NR51", NR51, 0xFF, 0xFF, 0x00, AllModels},
+	{"NR52", NR52, 0x8F, 0x80, 0x70, AllModels},
+	{"LCDC", LCDC, 0xFF, 0xFF, 0x00, AllModels},
+	{"STAT", STAT, 0x7F, 0x78, 0x80, AllModels},
+	{"SCY", SCY, 0xFF, 0xFF, 0x00, AllModels},
+	{"SCX", SCX, 0xFF, 0xFF, 0x00, AllModels},
+	{"LY", LY, 0xFF, 0x00, 0x00, AllModels},
+	{"LYC", LYC, 0xFF, 0xFF, 0x00, AllModels},
+	{"DMA", DMA, 0xFF, 0xFF, 0x00, AllModels},
+	{"BGP", BGP, 0xFF, 0xFF, 0x00, AllModels},
+	{"OBP0", OBP0, 0xFF, 0xFF, 0x00, AllModels},
+	{"OBP1", OBP1, 0xFF, 0xFF, 0x00, AllModels},
+	{"WY", WY, 0xFF, 0xFF, 0x00, AllModels},
+	{"WX", WX, 0xFF, 0xFF, 0x00, AllModels},
+	{"KEY1", KEY1, 0x81, 0x01, 0x7E, CGBMode},
+	{"VBK", VBK, 0x01, 0x01, 0xFE, CGBMode},
+	{"BOOT", BOOT, 0x00, 0x01, 0xFF, AllModels},
+	{"HDMA1", HDMA1, 0x00, 0xFF, 0xFF, CGBMode},
+	{"HDMA2", HDMA2, 0x00, 0xF0, 0xFF, CGBMode},
+	{"HDMA3", HDMA3, 0x00, 0x1F, 0xFF, CGBMode},
+	{"HDMA4", HDMA4, 0x00, 0xF0, 0xFF, CGBMode},
+	{"HDMA5", HDMA5, 0xFF, 0xFF, 0x00, CGBMode},
+	{"RP", RP, 0xC3, 0xC1, 0x3C, CGBMode},
+	{"BCPS", BCPS, 0xBF, 0xBF, 0x40, CGBMode},
+	{"BCPD", BCPD, 0xFF, 0xFF, 0x00, CGBMode},
+	{"OCPS", OCPS, 0xBF, 0xBF, 0x40, CGBMode},
+	{"OCPD", OCPD, 0xFF, 0xFF, 0x00, CGBMode},
+	{"OPRI", OPRI, 0x01, 0x01, 0xFE, CGBHardware},
+	{"SVBK", SVBK, 0x07, 0x07, 0xF8, CGBMode},
+	{"FF72", FF72, 0xFF, 0xFF, 0x00, CGBHardware},
+	{"FF73", FF73, 0xFF, 0xFF, 0x00, CGBHardware},
+	{"FF74", FF74, 0xFF, 0xFF, 0x00, CGBMode},
+	{"FF75", FF75, 0x70, 0x70, 0x8F, CGBHardware},
+	{"PCM12", PCM12, 0xFF, 0x00, 0x00, CGBHardware},
+	{"PCM34", PCM34, 0xFF, 0x00, 0x00, CGBHardware},
+}, waveRAM()...)
+
+// Returns the description of each byte of wave RAM.
+func waveRAM() []Register {
+	r := make([]Register, 16)
+	for i := range r {
+		r[i] = Register{fmt.Sprintf("WAVE%X", i), WaveRAM + uint16(i), 0xFF, 0xFF, 0x00, AllModels}
+	}
+	return r
+}
+
+// Returns the description of the register at address a
+// on model m running in CGB mode or not. Returns false
+// if there is no register at a.
+func Lookup(a uint16, m model.Model, cgbMode bool) (Register, bool) {
+	for _, r := range Registers {
+		if r.Addr == a && r.Models.On(m, cgbMode) {
+			return r, true
+		}
+	}
+	return Register{}, false
+}
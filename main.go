@@ -6,30 +6,39 @@ import (
 	"os"
 
 	"github.com/markelmencia/gogb/cartridge"
+	"github.com/markelmencia/gogb/cpu/instructions"
 )
 
 func main() {
-	var header bool
+	var header, jsonOutput bool
 	flag.BoolVar(&header, "header", false, "Prints information about the specified ROM file")
+	flag.BoolVar(&jsonOutput, "json", false, "Prints the information requested as JSON")
 	flag.Parse()
 	l := log.New(os.Stderr, "gogb: ", 0)
 
-	if len(os.Args) < 2 {
+	if flag.NArg() < 1 {
 		l.Fatal("not enough arguments: please specify the ROM path")
 	}
+	romPath := flag.Arg(0)
 
 	if header {
-		if len(os.Args) < 3 {
-			l.Fatal("not enough arguments: please specify the ROM path to read the cartridge header")
-		}
-		romPath := os.Args[2]
 		cart, err := cartridge.GetCartridgeData(romPath)
 		if err != nil {
-			log.Fatal(err)
+			l.Fatal(err)
+		}
+		if !jsonOutput {
+			if err := cartridge.PrintHeaderDataWith(cart, instructions.Disassemble); err != nil {
+				l.Fatal(err)
+			}
+			return // Execution ends
+		}
+
+		h, err := cartridge.ParseHeader(cart)
+		if err != nil {
+			l.Fatal(err)
 		}
-		hdErr := cartridge.PrintHeaderData(cart)
-		if hdErr != nil {
-			l.Fatal(hdErr)
+		if err := h.WriteJSON(os.Stdout); err != nil {
+			l.Fatal(err)
 		}
 		return // Execution ends
 	}
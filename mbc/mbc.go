@@ -0,0 +1,141 @@
+package mbc
+
+import (
+	"fmt"
+
+	"github.com/markelmencia/gogb/bus"
+	"github.com/markelmencia/gogb/cartridge"
+)
+
+/* INTERFACES */
+
+// Represents the memory bank controller of a cartridge.
+// It is mapped both at 0x0000-0x7FFF, where writes set
+// its registers, and at 0xA000-0xBFFF, where the
+// cartridge RAM is.
+type MBC interface {
+	bus.Region
+}
+
+// Represents a controller whose RAM keeps its contents
+// while the console is off, if the cartridge has a
+// battery (see HasBattery).
+type Battery interface {
+	// Returns a copy of the contents to persist.
+	SaveData() []byte
+	// Restores contents returned by SaveData.
+	LoadSaveData(data []byte) error
+}
+
+/* CONSTRUCTION */
+
+// Size in bytes of a ROM bank
+const ROMBankSize = 0x4000
+
+// Size in bytes of a RAM bank
+const RAMBankSize = 0x2000
+
+// Returns the memory bank controller of the cartridge
+// rom, according to the cartridge type in its header.
+//
+// Returns nil if the cartridge has no controller or
+// has one that is not supported, in which case the
+// first 32 KiB of the ROM are mapped directly.
+func New(rom []byte) MBC {
+	h, err := cartridge.ParseHeader(rom)
+	if err != nil {
+		return nil
+	}
+	ramSize := max(h.RAMSize, 0)
+
+	switch h.Type {
+	case 0x01:
+		return NewMBC1(rom, 0)
+	case 0x02, 0x03:
+		return NewMBC1(rom, ramSize)
+	case 0x05, 0x06:
+		// The RAM is inside the controller, so
+		// the header reports none
+		return NewMBC2(rom)
+	case 0x0F:
+		return NewMBC3(rom, 0, NewRTC())
+	case 0x10:
+		return NewMBC3(rom, ramSize, NewRTC())
+	case 0x11:
+		return NewMBC3(rom, 0, nil)
+	case 0x12, 0x13:
+		return NewMBC3(rom, ramSize, nil)
+	case 0x19:
+		return NewMBC5(rom, 0, false)
+	case 0x1A, 0x1B:
+		return NewMBC5(rom, ramSize, false)
+	case 0x1C:
+		return NewMBC5(rom, 0, true)
+	case 0x1D, 0x1E:
+		return NewMBC5(rom, ramSize, true)
+	}
+	return nil
+}
+
+// Returns true if cartridges of type cartType
+// have a battery to keep RAM contents.
+func HasBattery(cartType byte) bool {
+	switch cartType {
+	case 0x03, 0x06, 0x09, 0x0D, 0x0F, 0x10, 0x13, 0x1B, 0x1E, 0x22, 0xFF:
+		return true
+	}
+	return false
+}
+
+/* BANKS */
+
+// Represents the ROM and RAM of a cartridge,
+// as banks the controller maps.
+type banks struct {
+	rom []byte
+	ram []byte
+}
+
+// Returns the number of ROM banks.
+func (b banks) romBanks() int {
+	return max((len(b.rom)+ROMBankSize-1)/ROMBankSize, 1)
+}
+
+// Returns the byte at address a of ROM bank n. Banks past
+// the last one wrap around, as the unused bank bits are
+// not wired, and reads past the end of the ROM return
+// 0xFF.
+func (b banks) romByte(n int, a uint16) byte {
+	i := n%b.romBanks()*ROMBankSize + int(a&(ROMBankSize-1))
+	if i >= len(b.rom) {
+		return 0xFF
+	}
+	return b.rom[i]
+}
+
+// Returns a copy of the RAM.
+func (b banks) SaveData() []byte {
+	return append([]byte{}, b.ram...)
+}
+
+// Copies data into the RAM. It must be
+// exactly as long as the RAM.
+func (b banks) LoadSaveData(data []byte) error {
+	if len(data) != len(b.ram) {
+		return fmt.Errorf("Save data is %d bytes long (Expected: %d bytes)",
+			len(data), len(b.ram),
+		)
+	}
+	copy(b.ram, data)
+	return nil
+}
+
+// Returns the index into RAM of address a of RAM
+// bank n, or -1 if the cartridge has no RAM. RAM
+// smaller than the bank size is mirrored.
+func (b banks) ramIndex(n int, a uint16) int {
+	if len(b.ram) == 0 {
+		return -1
+	}
+	return (n*RAMBankSize + int(a-bus.ExternalRAMStart)) % len(b.ram)
+}
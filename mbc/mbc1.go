@@ -0,0 +1,115 @@
+package mbc
+
+import "github.com/markelmencia/gogb/cartridge"
+
+// Represents the MBC1 controller, for up to 2 MiB of
+// ROM and 32 KiB of RAM, or up to 1 MiB of ROM and
+// 8 KiB of RAM in mode 0.
+// (read https://gbdev.io/pandocs/MBC1.html)
+// for more information.
+type MBC1 struct {
+	banks
+	ramEnabled bool
+	// 5-bit ROM bank register (BANK1)
+	bank1 byte
+	// 2-bit register with the upper bits of the
+	// ROM bank, or the RAM bank (BANK2)
+	bank2 byte
+	// Banking mode. In mode 1 BANK2 also applies
+	// to 0x0000-0x3FFF and to RAM.
+	mode byte
+	// True on MBC1M multicarts, which leave out the
+	// top bit of BANK1 so BANK2 selects one of
+	// four 256 KiB games
+	multicart bool
+}
+
+// Returns an MBC1 for the cartridge rom with ramSize
+// bytes of RAM. MBC1M multicarts are detected by
+// their size (1 MiB) and by the header of a second
+// game being in bank 0x10.
+func NewMBC1(rom []byte, ramSize int) *MBC1 {
+	c := &MBC1{banks: banks{rom: rom, ram: make([]byte, ramSize)}, bank1: 1}
+	if len(rom) == 0x100000 {
+		c.multicart = cartridge.LogoMatches(rom[0x10*ROMBankSize:], false)
+	}
+	return c
+}
+
+// Returns true if the cartridge is an MBC1M multicart.
+func (c *MBC1) Multicart() bool {
+	return c.multicart
+}
+
+// Returns the number of bits of BANK1
+// wired into the ROM bank number.
+func (c *MBC1) bank1Bits() int {
+	if c.multicart {
+		return 4
+	}
+	return 5
+}
+
+// Returns the ROM bank mapped at 0x0000-0x3FFF
+// (zone 0) or 0x4000-0x7FFF (zone 1).
+func (c *MBC1) romBank(zone int) int {
+	bits := c.bank1Bits()
+	upper := int(c.bank2) << bits
+	if zone == 0 {
+		if c.mode == 0 {
+			return 0
+		}
+		return upper
+	}
+	return upper | int(c.bank1)&(1<<bits-1)
+}
+
+// Returns the RAM bank mapped at 0xA000-0xBFFF.
+func (c *MBC1) ramBank() int {
+	if c.mode == 0 {
+		return 0
+	}
+	return int(c.bank2)
+}
+
+// Returns the byte at address a of ROM or RAM. RAM
+// reads 0xFF while disabled.
+func (c *MBC1) GetByte(a uint16) byte {
+	switch {
+	case a < 0x4000:
+		return c.romByte(c.romBank(0), a)
+	case a < 0x8000:
+		return c.romByte(c.romBank(1), a)
+	}
+
+	i := c.ramIndex(c.ramBank(), a)
+	if !c.ramEnabled || i < 0 {
+		return 0xFF
+	}
+	return c.ram[i]
+}
+
+// Sets the register selected by address a to v, or v
+// into address a of RAM if it is enabled.
+func (c *MBC1) SetByte(v byte, a uint16) {
+	switch {
+	case a < 0x2000:
+		c.ramEnabled = v&0x0F == 0x0A
+	case a < 0x4000:
+		// Bank 0 can not be selected in 0x4000-0x7FFF, but
+		// only the 5 bits of BANK1 are checked, so banks
+		// 0x20, 0x40 and 0x60 map the next one instead
+		c.bank1 = v & 0x1F
+		if c.bank1 == 0 {
+			c.bank1 = 1
+		}
+	case a < 0x6000:
+		c.bank2 = v & 0x03
+	case a < 0x8000:
+		c.mode = v & 0x01
+	default:
+		if i := c.ramIndex(c.ramBank(), a); c.ramEnabled && i >= 0 {
+			c.ram[i] = v
+		}
+	}
+}
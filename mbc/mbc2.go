@@ -0,0 +1,70 @@
+package mbc
+
+// Size of the RAM inside MBC2, in half-bytes
+const mbc2RAMSize = 512
+
+// Represents the MBC2 controller, for up to 256 KiB of
+// ROM, with 512 half-bytes of RAM inside the controller.
+// (read https://gbdev.io/pandocs/MBC2.html)
+// for more information.
+type MBC2 struct {
+	banks
+	ramEnabled bool
+	// 4-bit ROM bank register
+	romBank byte
+}
+
+// Returns an MBC2 for the cartridge rom. Each byte of
+// RAM holds one half-byte, in its low nibble.
+func NewMBC2(rom []byte) *MBC2 {
+	return &MBC2{banks: banks{rom: rom, ram: make([]byte, mbc2RAMSize)}, romBank: 1}
+}
+
+// Returns the byte at address a of ROM or RAM. RAM
+// is mirrored across 0xA000-0xBFFF, its upper nibble
+// reads as 1s and it reads 0xFF while disabled.
+func (c *MBC2) GetByte(a uint16) byte {
+	switch {
+	case a < 0x4000:
+		return c.romByte(0, a)
+	case a < 0x8000:
+		return c.romByte(int(c.romBank), a)
+	}
+
+	if !c.ramEnabled {
+		return 0xFF
+	}
+	return 0xF0 | c.ram[int(a)%mbc2RAMSize]
+}
+
+// Sets the register selected by address a to v, or the
+// low nibble of v into address a of RAM if it is enabled.
+// Registers are only mapped at 0x0000-0x3FFF, and bit 8
+// of the address selects which one is written.
+func (c *MBC2) SetByte(v byte, a uint16) {
+	switch {
+	case a < 0x4000 && a&0x0100 == 0:
+		c.ramEnabled = v&0x0F == 0x0A
+	case a < 0x4000:
+		c.romBank = v & 0x0F
+		if c.romBank == 0 {
+			c.romBank = 1
+		}
+	case a >= 0xA000:
+		if c.ramEnabled {
+			c.ram[int(a)%mbc2RAMSize] = v & 0x0F
+		}
+	}
+}
+
+// Copies data into the RAM. It must be 512 bytes long,
+// and only the low nibble of each byte is kept.
+func (c *MBC2) LoadSaveData(data []byte) error {
+	if err := c.banks.LoadSaveData(data); err != nil {
+		return err
+	}
+	for i := range c.ram {
+		c.ram[i] &= 0x0F
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package mbc
+
+// Represents the MBC3 controller, for up to 2 MiB of
+// ROM and 32 KiB of RAM, optionally with a real-time
+// clock. ROMs larger than 2 MiB use the full 8 bits of
+// the ROM bank register, like MBC30 does.
+// (read https://gbdev.io/pandocs/MBC3.html)
+// for more information.
+type MBC3 struct {
+	banks
+	// Enables both RAM and the RTC registers
+	ramEnabled bool
+	romBank    byte
+	// RAM bank (0x00-0x07) or RTC register
+	// (0x08-0x0C) mapped at 0xA000-0xBFFF
+	ramBank byte
+	// Last value written into the latch register
+	latch byte
+	// Nil if the cartridge has no clock
+	RTC *RTC
+}
+
+// Returns an MBC3 for the cartridge rom with
+// ramSize bytes of RAM and the clock rtc,
+// which may be nil.
+func NewMBC3(rom []byte, ramSize int, rtc *RTC) *MBC3 {
+	return &MBC3{banks: banks{rom: rom, ram: make([]byte, ramSize)}, romBank: 1, latch: 0xFF, RTC: rtc}
+}
+
+// Returns the byte at address a of ROM, RAM or the
+// latched RTC register selected. RAM and the RTC read
+// 0xFF while disabled.
+func (c *MBC3) GetByte(a uint16) byte {
+	switch {
+	case a < 0x4000:
+		return c.romByte(0, a)
+	case a < 0x8000:
+		return c.romByte(int(c.romBank), a)
+	case !c.ramEnabled:
+		return 0xFF
+	case c.ramBank >= RTCSeconds && c.ramBank <= RTCDaysHigh && c.RTC != nil:
+		return c.RTC.Get(c.ramBank)
+	}
+
+	i := c.ramIndex(int(c.ramBank), a)
+	if c.ramBank > 0x07 || i < 0 {
+		return 0xFF
+	}
+	return c.ram[i]
+}
+
+// Sets the register selected by address a to v, or v into
+// address a of RAM or the RTC register selected.
+func (c *MBC3) SetByte(v byte, a uint16) {
+	switch {
+	case a < 0x2000:
+		c.ramEnabled = v&0x0F == 0x0A
+	case a < 0x4000:
+		c.romBank = v
+		if c.romBanks() <= 0x80 {
+			c.romBank &= 0x7F
+		}
+		if c.romBank == 0 {
+			c.romBank = 1
+		}
+	case a < 0x6000:
+		c.ramBank = v
+	case a < 0x8000:
+		// Writing 0x00 and then 0x01 latches the clock
+		if c.latch == 0x00 && v == 0x01 && c.RTC != nil {
+			c.RTC.Latch()
+		}
+		c.latch = v
+	case !c.ramEnabled:
+	case c.ramBank >= RTCSeconds && c.ramBank <= RTCDaysHigh && c.RTC != nil:
+		c.RTC.Set(c.ramBank, v)
+	default:
+		if i := c.ramIndex(int(c.ramBank), a); c.ramBank <= 0x07 && i >= 0 {
+			c.ram[i] = v
+		}
+	}
+}
+
+// Returns a copy of the RAM, followed by the
+// state of the clock if the cartridge has one
+// (see RTC.SaveData).
+func (c *MBC3) SaveData() []byte {
+	data := c.banks.SaveData()
+	if c.RTC != nil {
+		data = append(data, c.RTC.SaveData()...)
+	}
+	return data
+}
+
+// Restores RAM and, if data includes it, the state
+// of the clock from data returned by SaveData.
+func (c *MBC3) LoadSaveData(data []byte) error {
+	if c.RTC == nil || len(data) <= len(c.ram) {
+		return c.banks.LoadSaveData(data)
+	}
+	if err := c.RTC.LoadSaveData(data[len(c.ram):]); err != nil {
+		return err
+	}
+	return c.banks.LoadSaveData(data[:len(c.ram)])
+}
@@ -0,0 +1,92 @@
+package mbc
+
+// Represents the MBC5 controller, for up to 8 MiB of ROM
+// and 128 KiB of RAM. On rumble cartridges, bit 3 of the
+// RAM bank register drives the motor instead.
+// (read https://gbdev.io/pandocs/MBC5.html)
+// for more information.
+type MBC5 struct {
+	banks
+	ramEnabled bool
+	// 9-bit ROM bank register. Unlike on other
+	// controllers, bank 0 can be selected.
+	romBank uint16
+	// 4-bit RAM bank register
+	ramBank byte
+	// True if the cartridge has a rumble motor
+	rumble bool
+	motor  bool
+	// Called with the new state of the motor whenever
+	// it is turned on or off. May be nil.
+	OnRumble func(on bool)
+}
+
+// Returns an MBC5 for the cartridge rom with ramSize
+// bytes of RAM and, if rumble is true, a motor.
+func NewMBC5(rom []byte, ramSize int, rumble bool) *MBC5 {
+	return &MBC5{banks: banks{rom: rom, ram: make([]byte, ramSize)}, romBank: 1, rumble: rumble}
+}
+
+// Returns true if the cartridge has a rumble motor.
+func (c *MBC5) Rumble() bool {
+	return c.rumble
+}
+
+// Returns true if the rumble motor is on.
+func (c *MBC5) Motor() bool {
+	return c.motor
+}
+
+// Returns the byte at address a of ROM or RAM.
+// RAM reads 0xFF while disabled.
+func (c *MBC5) GetByte(a uint16) byte {
+	switch {
+	case a < 0x4000:
+		return c.romByte(0, a)
+	case a < 0x8000:
+		return c.romByte(int(c.romBank), a)
+	}
+
+	i := c.ramIndex(int(c.ramBank), a)
+	if !c.ramEnabled || i < 0 {
+		return 0xFF
+	}
+	return c.ram[i]
+}
+
+// Sets the register selected by address a to v, or v
+// into address a of RAM if it is enabled.
+func (c *MBC5) SetByte(v byte, a uint16) {
+	switch {
+	case a < 0x2000:
+		// All 8 bits are checked, unlike on MBC1
+		c.ramEnabled = v == 0x0A
+	case a < 0x3000:
+		c.romBank = c.romBank&0x100 | uint16(v)
+	case a < 0x4000:
+		c.romBank = c.romBank&0xFF | uint16(v&0x01)<<8
+	case a < 0x6000:
+		c.ramBank = v & 0x0F
+		if c.rumble {
+			c.ramBank &= 0x07
+			c.setMotor(v&0x08 != 0)
+		}
+	case a < 0x8000:
+	default:
+		if i := c.ramIndex(int(c.ramBank), a); c.ramEnabled && i >= 0 {
+			c.ram[i] = v
+		}
+	}
+}
+
+// Turns the motor on or off, reporting
+// the change to OnRumble.
+func (c *MBC5) setMotor(on bool) {
+	if c.motor == on {
+		return
+	}
+	c.motor = on
+	if c.OnRumble != nil {
+		c.OnRumble(on)
+	}
+}
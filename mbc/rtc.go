@@ -0,0 +1,237 @@
+package mbc
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+// RTC registers, as selected through the
+// RAM bank register of MBC3
+const (
+	RTCSeconds byte = 0x08
+	RTCMinutes byte = 0x09
+	RTCHours   byte = 0x0A
+	// Low 8 bits of the day counter
+	RTCDaysLow byte = 0x0B
+	// Bit 0: bit 8 of the day counter,
+	// bit 6: halt, bit 7: day counter carry
+	RTCDaysHigh byte = 0x0C
+)
+
+// Length in bytes of the RTC save data
+const RTCSaveDataSize = 48
+
+// Represents the values of the RTC registers.
+type rtcRegisters struct {
+	seconds, minutes, hours byte
+	// 9-bit day counter
+	days uint16
+	// True if the clock is stopped
+	halt bool
+	// True if the day counter overflowed
+	carry bool
+}
+
+// Returns true if every register holds a value the
+// clock can reach by counting.
+func (r rtcRegisters) valid() bool {
+	return r.seconds < 60 && r.minutes < 60 && r.hours < 24
+}
+
+// Advances the registers by one second. Registers
+// holding values past their range count up to their
+// bit width and wrap around to 0 without carrying.
+func (r *rtcRegisters) tick() {
+	if r.seconds = (r.seconds + 1) & 0x3F; r.seconds != 60 {
+		return
+	}
+	r.seconds = 0
+	if r.minutes = (r.minutes + 1) & 0x3F; r.minutes != 60 {
+		return
+	}
+	r.minutes = 0
+	if r.hours = (r.hours + 1) & 0x1F; r.hours != 24 {
+		return
+	}
+	r.hours = 0
+	r.addDays(1)
+}
+
+// Advances the day counter by n days, setting the
+// carry flag if it overflows.
+func (r *rtcRegisters) addDays(n uint64) {
+	days := uint64(r.days) + n
+	if days > 0x1FF {
+		r.carry = true
+	}
+	r.days = uint16(days & 0x1FF)
+}
+
+// Advances the registers by n seconds.
+func (r *rtcRegisters) advance(n uint64) {
+	for ; n > 0 && !r.valid(); n-- {
+		r.tick()
+	}
+	if n == 0 {
+		return
+	}
+
+	total := uint64(r.seconds) + uint64(r.minutes)*60 + uint64(r.hours)*3600 + n
+	r.seconds = byte(total % 60)
+	r.minutes = byte(total / 60 % 60)
+	r.hours = byte(total / 3600 % 24)
+	r.addDays(total / 86400)
+}
+
+// Returns the value of register reg.
+func (r rtcRegisters) get(reg byte) byte {
+	switch reg {
+	case RTCSeconds:
+		return r.seconds
+	case RTCMinutes:
+		return r.minutes
+	case RTCHours:
+		return r.hours
+	case RTCDaysLow:
+		return byte(r.days)
+	case RTCDaysHigh:
+		v := byte(r.days>>8) & 0x01
+		if r.halt {
+			v |= 0x40
+		}
+		if r.carry {
+			v |= 0x80
+		}
+		return v
+	}
+	return 0xFF
+}
+
+// Sets v into register reg, keeping
+// only the bits the register has.
+func (r *rtcRegisters) set(reg byte, v byte) {
+	switch reg {
+	case RTCSeconds:
+		r.seconds = v & 0x3F
+	case RTCMinutes:
+		r.minutes = v & 0x3F
+	case RTCHours:
+		r.hours = v & 0x1F
+	case RTCDaysLow:
+		r.days = r.days&0x100 | uint16(v)
+	case RTCDaysHigh:
+		r.days = r.days&0xFF | uint16(v&0x01)<<8
+		r.halt = v&0x40 != 0
+		r.carry = v&0x80 != 0
+	}
+}
+
+// Represents the real-time clock of MBC3 cartridges.
+// The registers the game reads are a copy of the
+// counting ones, taken when the clock is latched.
+// (read https://gbdev.io/pandocs/MBC3.html)
+// for more information.
+type RTC struct {
+	// Source of the current time the clock counts from.
+	// Defaults to time.Now. It is called on every
+	// access, so replacing it while the clock runs
+	// makes it jump by the difference between both.
+	Now func() time.Time
+
+	live    rtcRegisters
+	latched rtcRegisters
+	// Time the live registers were last advanced
+	// to. Zero until the clock is first accessed.
+	last time.Time
+}
+
+// Returns a clock counting from time.Now,
+// with every register cleared.
+func NewRTC() *RTC {
+	return &RTC{Now: time.Now}
+}
+
+// Advances the live registers by the
+// whole seconds elapsed since last.
+func (r *RTC) update() {
+	now := r.Now()
+	if r.last.IsZero() || r.live.halt {
+		r.last = now
+		return
+	}
+
+	elapsed := now.Sub(r.last)
+	if elapsed < time.Second {
+		return
+	}
+	seconds := uint64(elapsed / time.Second)
+	r.live.advance(seconds)
+	r.last = r.last.Add(time.Duration(seconds) * time.Second)
+}
+
+// Copies the live registers into the ones
+// that are read.
+func (r *RTC) Latch() {
+	r.update()
+	r.latched = r.live
+}
+
+// Returns the latched value of register reg. Bits
+// the register does not have read as 0.
+func (r *RTC) Get(reg byte) byte {
+	return r.latched.get(reg)
+}
+
+// Sets v into the live register reg. Writing the seconds
+// restarts the current second, and setting the halt bit
+// stops the clock until it is cleared.
+func (r *RTC) Set(reg byte, v byte) {
+	r.update()
+	r.live.set(reg, v)
+	if reg == RTCSeconds {
+		r.last = r.Now()
+	}
+}
+
+// Returns the state of the clock in the format most
+// emulators append to save files: the live and then the
+// latched registers as 32-bit little endian values,
+// followed by the time they were saved at, as a 64-bit
+// little endian Unix timestamp.
+func (r *RTC) SaveData() []byte {
+	r.update()
+	data := make([]byte, 0, RTCSaveDataSize)
+	for _, regs := range []rtcRegisters{r.live, r.latched} {
+		for reg := RTCSeconds; reg <= RTCDaysHigh; reg++ {
+			data = binary.LittleEndian.AppendUint32(data, uint32(regs.get(reg)))
+		}
+	}
+	return binary.LittleEndian.AppendUint64(data, uint64(r.last.Unix()))
+}
+
+// Restores the state of the clock from data returned
+// by SaveData. The time elapsed since it was saved
+// is counted on the next access. The timestamp may
+// also be 32-bit, as some emulators save it.
+func (r *RTC) LoadSaveData(data []byte) error {
+	if len(data) != RTCSaveDataSize && len(data) != RTCSaveDataSize-4 {
+		return fmt.Errorf("RTC save data is %d bytes long (Expected: %d bytes)",
+			len(data), RTCSaveDataSize,
+		)
+	}
+
+	for i, regs := range []*rtcRegisters{&r.live, &r.latched} {
+		for reg := RTCSeconds; reg <= RTCDaysHigh; reg++ {
+			offset := (i*5 + int(reg-RTCSeconds)) * 4
+			regs.set(reg, byte(binary.LittleEndian.Uint32(data[offset:])))
+		}
+	}
+
+	timestamp := int64(binary.LittleEndian.Uint32(data[40:]))
+	if len(data) == RTCSaveDataSize {
+		timestamp = int64(binary.LittleEndian.Uint64(data[40:]))
+	}
+	r.last = time.Unix(timestamp, 0)
+	return nil
+}
@@ -0,0 +1,46 @@
+package model
+
+// Defines a Game Boy hardware model.
+type Model byte
+
+// Defines an enum with each hardware model.
+const (
+	DMG  Model = iota // Game Boy
+	DMG0              // Early Japanese Game Boy
+	MGB               // Game Boy Pocket
+	SGB               // Super Game Boy
+	SGB2              // Super Game Boy 2
+	CGB               // Game Boy Color
+	AGB               // Game Boy Advance
+)
+
+// Names of each model.
+var modelToString = map[Model]string{
+	DMG:  "DMG",
+	DMG0: "DMG0",
+	MGB:  "MGB",
+	SGB:  "SGB",
+	SGB2: "SGB2",
+	CGB:  "CGB",
+	AGB:  "AGB",
+}
+
+// Returns the name of the model.
+func (m Model) String() string {
+	s, ok := modelToString[m]
+	if !ok {
+		return "Unknown model"
+	}
+	return s
+}
+
+// Returns true if the model has Game Boy Color
+// hardware, which is also the case for the AGB.
+func (m Model) IsColor() bool {
+	return m == CGB || m == AGB
+}
+
+// Returns true if the model is a Super Game Boy.
+func (m Model) IsSuper() bool {
+	return m == SGB || m == SGB2
+}
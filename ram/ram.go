@@ -1,5 +1,7 @@
 package ram
 
+// Represents a flat 64 KiB memory with no regions,
+// used as a stand-in for the bus in unit tests.
 type RAM [65536]byte
 
 // Returns the byte stored in the
@@ -0,0 +1,77 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/markelmencia/gogb/emulator"
+	"github.com/markelmencia/gogb/model"
+)
+
+func TestWRAMBanking(t *testing.T) {
+	emu := getEmulation(t, getCGBProgramROM(), emulator.Config{Model: model.CGB})
+
+	for bank := range 8 {
+		emu.Write(byte(bank), 0xFF70)
+		emu.Write(byte(0x10+bank), 0xD000)
+	}
+	emu.Write(0x99, 0xC000)
+
+	// Bank 0 selects bank 1
+	emu.Write(0x00, 0xFF70)
+	if emu.Read(0xD000) != 0x11 {
+		t.Fatal("Unexpected value in WRAM bank 1")
+	}
+	if emu.Read(0xFF70) != 0xF8 {
+		t.Fatal("Unexpected SVBK value")
+	}
+
+	emu.Write(0xFD, 0xFF70)
+	if emu.Read(0xD000) != 0x15 || emu.Read(0xF000) != 0x15 {
+		t.Fatal("Unexpected value in WRAM bank 5")
+	}
+	if emu.Read(0xC000) != 0x99 {
+		t.Fatal("WRAM bank 0 was switched")
+	}
+	if emu.Read(0xFF70) != 0xFD {
+		t.Fatal("Unexpected SVBK value")
+	}
+}
+
+func TestVRAMBanking(t *testing.T) {
+	emu := getEmulation(t, getCGBProgramROM(), emulator.Config{Model: model.CGB})
+
+	emu.Write(0x12, 0x8000)
+	emu.Write(0x01, 0xFF4F)
+	emu.Write(0x34, 0x8000)
+	if emu.Read(0xFF4F) != 0xFF {
+		t.Fatal("Unexpected VBK value")
+	}
+
+	emu.Write(0x00, 0xFF4F)
+	if emu.Read(0x8000) != 0x12 || emu.Read(0xFF4F) != 0xFE {
+		t.Fatal("Unexpected value in VRAM bank 0")
+	}
+}
+
+func TestBankingIgnoredOnDMG(t *testing.T) {
+	for _, c := range []struct {
+		model model.Model
+		rom   []byte
+	}{
+		{model.DMG, getHeaderROM("GAME", 0x80, 0x00, 0x12)},
+		{model.CGB, getHeaderROM("GAME", 0x00, 0x00, 0x12)}, // DMG compatibility mode
+	} {
+		emu := getEmulation(t, c.rom, emulator.Config{Model: c.model})
+		emu.Write(0x12, 0xD000)
+		emu.Write(0x56, 0x8000)
+
+		emu.Write(0x02, 0xFF70)
+		emu.Write(0x01, 0xFF4F)
+		if emu.Read(0xD000) != 0x12 || emu.Read(0x8000) != 0x56 {
+			t.Fatalf("Bank switched on %s", c.model)
+		}
+		if emu.Read(0xFF70) != 0xFF || emu.Read(0xFF4F) != 0xFF {
+			t.Fatalf("Unexpected bank register value on %s", c.model)
+		}
+	}
+}
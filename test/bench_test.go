@@ -0,0 +1,61 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/markelmencia/gogb/cpu"
+	"github.com/markelmencia/gogb/emulator"
+)
+
+// Program that copies 0x1000 bytes from ROM to WRAM over
+// and over, a typical mix of loads, 16-bit arithmetic,
+// logic and jumps.
+var copyProgram = []byte{
+	0x21, 0x00, 0x10, // 0x0100: LD HL, 0x1000
+	0x11, 0x00, 0xC0, // 0x0103: LD DE, 0xC000
+	0x01, 0x00, 0x10, // 0x0106: LD BC, 0x1000
+	0x2A,             // 0x0109: LD A, (HL+)
+	0x12,             // 0x010A: LD (DE), A
+	0x13,             // 0x010B: INC DE
+	0x0B,             // 0x010C: DEC BC
+	0x78,             // 0x010D: LD A, B
+	0xB1,             // 0x010E: OR C
+	0xC2, 0x09, 0x01, // 0x010F: JP NZ, 0x0109
+	0xC3, 0x00, 0x01, // 0x0112: JP 0x0100
+}
+
+// Runs copyProgram with the given engine, reporting
+// the instructions executed per second.
+func benchmarkEngine(b *testing.B, engine emulator.Engine) {
+	rom := make([]byte, 0x8000)
+	copy(rom[0x0100:], copyProgram)
+	emu, err := emulator.New(rom, emulator.Config{Engine: engine})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for b.Loop() {
+		emu.Step()
+	}
+	b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "instructions/s")
+}
+
+func BenchmarkInterpreter(b *testing.B) {
+	benchmarkEngine(b, emulator.EngineInterpreter)
+}
+
+func BenchmarkCachedBlocks(b *testing.B) {
+	benchmarkEngine(b, emulator.EngineCachedBlocks)
+}
+
+func BenchmarkRegisterAccess(b *testing.B) {
+	c := &cpu.CPU{}
+	b.ReportAllocs()
+	for b.Loop() {
+		c.SetHalve(cpu.B, c.GetHalve(cpu.C)+1)
+		c.SetReg(cpu.HL, c.GetReg(cpu.DE)+1)
+		c.SetFlag(!c.IsFlag(cpu.FlagZ), cpu.FlagZ)
+	}
+}
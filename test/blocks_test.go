@@ -0,0 +1,215 @@
+package test
+
+import (
+	"errors"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/markelmencia/gogb/bus"
+	"github.com/markelmencia/gogb/cpu"
+	"github.com/markelmencia/gogb/emulator"
+)
+
+// Runs rom for the given number of steps with both the
+// interpreter and the block cache, failing the test as soon
+// as the state of both emulations differs.
+func runDifferential(t *testing.T, rom []byte, steps int) (*emulator.Emulation, *emulator.Emulation) {
+	interpreter := getEmulation(t, rom, emulator.Config{})
+	cached := getEmulation(t, rom, emulator.Config{Engine: emulator.EngineCachedBlocks})
+
+	for i := range steps {
+		pc := interpreter.CPU.PC
+		m1, err1 := interpreter.Step()
+		m2, err2 := cached.Step()
+
+		if m1 != m2 || (err1 == nil) != (err2 == nil) {
+			t.Fatalf("Unexpected step result at step %d (PC: 0x%04X)", i, pc)
+		}
+
+		if *interpreter.CPU != *cached.CPU {
+			t.Fatalf("Unexpected CPU state at step %d (PC: 0x%04X)", i, pc)
+		}
+
+		// Comparing the whole memory is slow, so it is
+		// only done every now and then and at the end
+		if (i%500 == 0 || i == steps-1) && !sameMemory(interpreter.RAM, cached.RAM, [2]int{0x0000, 0x10000}) {
+			t.Fatalf("Unexpected memory contents at step %d (PC: 0x%04X)", i, pc)
+		}
+
+		if interpreter.Clock.Cycles != cached.Clock.Cycles {
+			t.Fatalf("Unexpected clock at step %d (PC: 0x%04X)", i, pc)
+		}
+	}
+	return interpreter, cached
+}
+
+// Returns true if both buses hold the same value at
+// every address of the given [start, end) ranges.
+func sameMemory(a, b bus.Bus, ranges ...[2]int) bool {
+	for _, r := range ranges {
+		for i := r[0]; i < r[1]; i++ {
+			if a.GetByte(uint16(i)) != b.GetByte(uint16(i)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func TestBlockCacheSelfModifyingCode(t *testing.T) {
+	rom := make([]byte, 0x8000)
+	copy(rom[0x0100:], []byte{
+		0x21, 0x00, 0xC0, // LD HL, 0xC000
+		0x36, 0x3C, // LD (HL), 0x3C (INC A)
+		0x23,       // INC HL
+		0x36, 0xC9, // LD (HL), 0xC9 (RET)
+		0xCD, 0x00, 0xC0, // CALL 0xC000
+		0x21, 0x00, 0xC0, // LD HL, 0xC000
+		0x36, 0x04, // LD (HL), 0x04 (INC B)
+		0xCD, 0x00, 0xC0, // CALL 0xC000
+		0x76, // HALT
+	})
+
+	_, cached := runDifferential(t, rom, 12)
+
+	if cached.CPU.GetHalve(cpu.A) != 0x02 || cached.CPU.BC != 0x0113 {
+		t.Fatal("Unexpected register values")
+	}
+}
+
+func TestBlockCacheBootROMUnmap(t *testing.T) {
+	rom := getHeaderROM("GAME", 0x00, 0x00, 0x12)
+	rom[0x0004] = 0x3C // INC A
+	boot := getBootROM(emulator.DMGBootROMSize)
+	boot[0x0004] = 0x04 // INC B
+
+	cached := getEmulation(t, rom, emulator.Config{
+		BootROM: boot,
+		Engine:  emulator.EngineCachedBlocks,
+	})
+	for range 3 {
+		cached.Step()
+	}
+
+	if cached.CPU.BC != 0x0000 || cached.CPU.AF>>8 != 0x02 {
+		t.Fatal("Unexpected register values")
+	}
+}
+
+func TestBlockCacheEchoWrite(t *testing.T) {
+	rom := make([]byte, 0x8000)
+	copy(rom[0x0100:], []byte{
+		0x21, 0x00, 0xC0, // 0x0100: LD HL, 0xC000
+		0x36, 0x3C, // 0x0103: LD (HL), 0x3C (INC A)
+		0x23,       // 0x0105: INC HL
+		0x36, 0xC3, // 0x0106: LD (HL), 0xC3 (JP 0x0114)
+		0x23,       // 0x0108: INC HL
+		0x36, 0x14, // 0x0109: LD (HL), 0x14
+		0x23,       // 0x010B: INC HL
+		0x36, 0x01, // 0x010C: LD (HL), 0x01
+		0xC3, 0x00, 0xC0, // 0x010E: JP 0xC000
+	})
+	// Rewrites the code in WRAM through echo RAM
+	copy(rom[0x0114:], []byte{
+		0x21, 0x00, 0xE0, // 0x0114: LD HL, 0xE000
+		0x36, 0x04, // 0x0117: LD (HL), 0x04 (INC B)
+		0xC3, 0x00, 0xC0, // 0x0119: JP 0xC000
+	})
+
+	_, cached := runDifferential(t, rom, 20)
+	if cached.CPU.AF>>8 != 0x02 || cached.CPU.BC>>8 == 0x00 {
+		t.Fatal("Unexpected register values")
+	}
+}
+
+func TestBlockCacheRandomCode(t *testing.T) {
+	for seed := range uint64(16) {
+		r := rand.New(rand.NewPCG(seed, seed))
+		rom := make([]byte, 0x8000)
+		for i := range rom {
+			// Leave out what would stop the CPU for good
+			op := byte(r.Uint32())
+			for emulator.OpcodeLengths[op] == 0 || op == 0x10 || op == 0x76 {
+				op = byte(r.Uint32())
+			}
+			rom[i] = op
+		}
+		// Some code also runs from WRAM
+		for i := 0; i < len(rom); i += 0x1000 {
+			rom[i+0x0F] = 0xC3 // JP 0xC000
+			rom[i+0x10] = 0x00
+			rom[i+0x11] = 0xC0
+		}
+
+		runDifferential(t, rom, 5000)
+	}
+}
+
+func TestEngineDifferential(t *testing.T) {
+	rom := make([]byte, 0x8000)
+	copy(rom[0x0100:], copyProgram)
+	emu := getEmulation(t, rom, emulator.Config{Engine: emulator.EngineDifferential, RandomizeRAM: true})
+	emu.FaultPolicy = emulator.FaultStop
+
+	for range 1000 {
+		if _, err := emu.Step(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Changing the emulation from outside makes it diverge
+	emu.CPU.BC ^= 0x0100
+	pc := emu.CPU.PC
+	_, err := emu.Step()
+	var div *emulator.DivergenceError
+	if !errors.As(err, &div) || div.PC != pc || div.Cached.BC == div.Interpreter.BC {
+		t.Fatal("Divergence was not detected")
+	}
+
+	// Only the first divergence is reported
+	if _, err := emu.Step(); err != nil {
+		t.Fatal("Unexpected error after the first divergence")
+	}
+}
+
+func TestEngineDifferentialDefaultPolicy(t *testing.T) {
+	rom := make([]byte, 0x8000)
+	copy(rom[0x0100:], copyProgram)
+	emu := getEmulation(t, rom, emulator.Config{Engine: emulator.EngineDifferential})
+
+	emu.CPU.BC ^= 0x0100
+	_, err := emu.Step()
+	var div *emulator.DivergenceError
+	if !errors.As(err, &div) {
+		t.Fatal("Divergence was not reported with the default fault policy")
+	}
+}
+
+func TestEngineDifferentialMemory(t *testing.T) {
+	rom := make([]byte, 0x8000)
+	copy(rom[0x0100:], []byte{
+		0x21, 0x00, 0xC0, // 0x0100: LD HL, 0xC000
+		0x34,       // 0x0103: INC (HL)
+		0x18, 0xFD, // 0x0104: JR 0x0103
+	})
+	emu := getEmulation(t, rom, emulator.Config{Engine: emulator.EngineDifferential})
+
+	for range 10 {
+		if _, err := emu.Step(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Only the memory differs, registers stay the same
+	emu.RAM.SetByte(emu.RAM.GetByte(0xC000)+0x10, 0xC000)
+	for emu.CPU.PC != 0x0103 {
+		if _, err := emu.Step(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, err := emu.Step()
+	var div *emulator.DivergenceError
+	if !errors.As(err, &div) || div.PC != 0x0103 || div.Address != 0xC000 {
+		t.Fatal("Divergence was not detected")
+	}
+}
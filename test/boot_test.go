@@ -0,0 +1,183 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/markelmencia/gogb/cartridge"
+	"github.com/markelmencia/gogb/emulator"
+	"github.com/markelmencia/gogb/model"
+)
+
+// Returns a DMG boot ROM that loads 0x01 into A
+// and writes it into 0xFF50, unmapping itself.
+func getBootROM(size int) []byte {
+	boot := make([]byte, size)
+	copy(boot, []byte{
+		0x3E, 0x01, // LD A, 0x01
+		0xE0, 0x50, // LDH (0x50), A
+	})
+	return boot
+}
+
+func TestBootROMStart(t *testing.T) {
+	rom := getHeaderROM("GAME", 0x00, 0x00, 0x12)
+	emu := getEmulation(t, rom, emulator.Config{BootROM: getBootROM(emulator.DMGBootROMSize)})
+
+	if emu.CPU.PC != 0x0000 || emu.CPU.AF != 0x0000 {
+		t.Fatal("Unexpected register values")
+	}
+
+	if !emu.BootROMMapped() {
+		t.Fatal("Boot ROM not mapped")
+	}
+
+	if emu.Read(0x0000) != 0x3E {
+		t.Fatal("Unexpected value at 0x0000")
+	}
+}
+
+func TestBootROMUnmap(t *testing.T) {
+	rom := getHeaderROM("GAME", 0x00, 0x00, 0x12)
+	rom[0x0000] = 0xAA
+	emu := getEmulation(t, rom, emulator.Config{BootROM: getBootROM(emulator.DMGBootROMSize)})
+
+	emu.Step()
+	emu.Step()
+
+	if emu.BootROMMapped() {
+		t.Fatal("Boot ROM still mapped")
+	}
+
+	if emu.Read(0x0000) != 0xAA {
+		t.Fatal("Unexpected value at 0x0000")
+	}
+
+	// Writing to 0xFF50 again must not map it back
+	emu.Write(0x00, 0xFF50)
+	if emu.BootROMMapped() {
+		t.Fatal("Boot ROM mapped again")
+	}
+}
+
+func TestBootROMCGBHeaderGap(t *testing.T) {
+	rom := getHeaderROM("GAME", 0x80, 0x00, 0x12)
+	boot := getBootROM(emulator.CGBBootROMSize)
+	boot[0x0134] = 0xFF
+	boot[0x0200] = 0xBB
+	emu := getEmulation(t, rom, emulator.Config{Model: model.CGB, BootROM: boot})
+
+	if emu.Read(0x0134) != 'G' {
+		t.Fatal("Boot ROM covers the cartridge header")
+	}
+
+	if emu.Read(0x0200) != 0xBB {
+		t.Fatal("Unexpected value at 0x0200")
+	}
+}
+
+func TestBootROMSize(t *testing.T) {
+	rom := getHeaderROM("GAME", 0x00, 0x00, 0x12)
+	_, err := emulator.New(rom, emulator.Config{Model: model.CGB, BootROM: getBootROM(emulator.DMGBootROMSize)})
+	if err == nil {
+		t.Fatal("Unexpected nil error")
+	}
+}
+
+// Returns a DMG boot ROM that checks the cartridge header
+// like the real one does: it locks up at 0x00F0 unless
+// both the logo and the header checksum match, and
+// otherwise unmaps itself from 0x00FC, handing off
+// to the cartridge entry point.
+func getCheckingBootROM() []byte {
+	boot := make([]byte, emulator.DMGBootROMSize)
+	copy(boot, []byte{
+		0x31, 0xFE, 0xFF, // 0x0000: LD SP, 0xFFFE
+		0x11, 0x04, 0x01, // 0x0003: LD DE, 0x0104
+		0x21, 0xA8, 0x00, // 0x0006: LD HL, 0x00A8
+		0x06, 0x30, // 0x0009: LD B, 0x30
+		0x1A,             // 0x000B: LD A, (DE)
+		0x13,             // 0x000C: INC DE
+		0xBE,             // 0x000D: CP (HL)
+		0xC2, 0xF0, 0x00, // 0x000E: JP NZ, 0x00F0
+		0x23,             // 0x0011: INC HL
+		0x05,             // 0x0012: DEC B
+		0xC2, 0x0B, 0x00, // 0x0013: JP NZ, 0x000B
+		0xCD, 0x40, 0x00, // 0x0016: CALL 0x0040
+		0xC2, 0xF0, 0x00, // 0x0019: JP NZ, 0x00F0
+		0xC3, 0xFC, 0x00, // 0x001C: JP 0x00FC
+	})
+
+	// Adds 0x19 and every byte in 0x0134-0x014D, which
+	// is 0 if the checksum matches, keeping BC like the
+	// real boot ROM routines do
+	copy(boot[0x0040:], []byte{
+		0xC5,             // 0x0040: PUSH BC
+		0x21, 0x34, 0x01, // 0x0041: LD HL, 0x0134
+		0x06, 0x19, // 0x0044: LD B, 0x19
+		0x78,             // 0x0046: LD A, B
+		0x86,             // 0x0047: ADD A, (HL)
+		0x23,             // 0x0048: INC HL
+		0x05,             // 0x0049: DEC B
+		0xC2, 0x47, 0x00, // 0x004A: JP NZ, 0x0047
+		0x86, // 0x004D: ADD A, (HL)
+		0xC1, // 0x004E: POP BC
+		0xC9, // 0x004F: RET
+	})
+	copy(boot[0x00A8:], nintendoLogo)
+	copy(boot[0x00F0:], []byte{0xC3, 0xF0, 0x00}) // 0x00F0: JP 0x00F0
+	copy(boot[0x00FC:], []byte{
+		0x3E, 0x01, // 0x00FC: LD A, 0x01
+		0xE0, 0x50, // 0x00FE: LDH (0x50), A
+	})
+	return boot
+}
+
+func TestBootROMHeaderChecks(t *testing.T) {
+	valid := getHeaderROM("GAME", 0x00, 0x00, 0x00)
+	copy(valid[0x0104:], nintendoLogo)
+	valid[0x014D] = cartridge.GetCartHDChecksum(valid)
+
+	badLogo := append([]byte{}, valid...)
+	badLogo[0x0120] ^= 0xFF
+	badChecksum := append([]byte{}, valid...)
+	badChecksum[0x014D]++
+
+	for _, c := range []struct {
+		name     string
+		rom      []byte
+		handsOff bool
+	}{
+		{"valid header", valid, true},
+		{"bad logo", badLogo, false},
+		{"bad checksum", badChecksum, false},
+	} {
+		emu := getEmulation(t, c.rom, emulator.Config{BootROM: getCheckingBootROM()})
+		for range 10000 {
+			if emu.CPU.PC == 0x0100 {
+				break
+			}
+			emu.Step()
+		}
+
+		if c.handsOff && (emu.CPU.PC != 0x0100 || emu.BootROMMapped()) {
+			t.Fatalf("Boot ROM did not hand off with a %s", c.name)
+		}
+		if !c.handsOff && (emu.CPU.PC < 0x00F0 || emu.CPU.PC > 0x00F3 || !emu.BootROMMapped()) {
+			t.Fatalf("Boot ROM did not lock up with a %s", c.name)
+		}
+	}
+}
+
+func TestBootROMSTOP(t *testing.T) {
+	rom := getHeaderROM("GAME", 0x00, 0x00, 0x12)
+	boot := getBootROM(emulator.DMGBootROMSize)
+	copy(boot, []byte{0x10, 0x00}) // STOP
+	emu := getEmulation(t, rom, emulator.Config{BootROM: boot})
+
+	for range 3 {
+		emu.Step()
+	}
+	if !emu.CPU.Stopped || emu.CPU.PC != 0x0002 {
+		t.Fatal("CPU left STOP without joypad input")
+	}
+}
@@ -0,0 +1,114 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/markelmencia/gogb/bus"
+	"github.com/markelmencia/gogb/model"
+	"github.com/markelmencia/gogb/ram"
+)
+
+// The flat RAM can stand in for the bus in unit tests.
+var _ bus.Bus = &ram.RAM{}
+
+// Region that records the last address accessed.
+type recorder struct {
+	last uint16
+}
+
+func (r *recorder) GetByte(a uint16) byte {
+	r.last = a
+	return 0
+}
+
+func (r *recorder) SetByte(v byte, a uint16) {
+	r.last = a
+}
+
+func TestBusDispatch(t *testing.T) {
+	m := bus.NewMap(nil, model.DMG)
+	regions := []*bus.Region{
+		&m.Cartridge, &m.VRAM, &m.ExternalRAM, &m.WRAM, &m.Echo,
+		&m.OAM, &m.Unusable, &m.IO, &m.HRAM,
+	}
+	recorders := make([]*recorder, len(regions))
+	for i, r := range regions {
+		recorders[i] = &recorder{}
+		*r = recorders[i]
+	}
+
+	for _, c := range []struct {
+		a      uint16
+		region int
+	}{
+		{0x0000, 0}, {0x7FFF, 0}, {0x8000, 1}, {0x9FFF, 1}, {0xA000, 2}, {0xBFFF, 2},
+		{0xC000, 3}, {0xDFFF, 3}, {0xE000, 4}, {0xFDFF, 4}, {0xFE00, 5}, {0xFE9F, 5},
+		{0xFEA0, 6}, {0xFEFF, 6}, {0xFF00, 7}, {0xFF7F, 7}, {0xFF80, 8}, {0xFFFF, 8},
+	} {
+		m.GetByte(c.a)
+		if recorders[c.region].last != c.a {
+			t.Fatalf("Read from 0x%04X not dispatched to the expected region", c.a)
+		}
+
+		m.SetByte(0x00, c.a^0x0001)
+		if recorders[c.region].last != c.a^0x0001 {
+			t.Fatalf("Write to 0x%04X not dispatched to the expected region", c.a^0x0001)
+		}
+	}
+}
+
+func TestBusROM(t *testing.T) {
+	m := bus.NewMap([]byte{0x12, 0x34}, model.DMG)
+
+	m.SetByte(0x99, 0x0000)
+	if m.GetByte(0x0000) != 0x12 {
+		t.Fatal("ROM was written")
+	}
+
+	if m.GetByte(0x0002) != 0xFF {
+		t.Fatal("Unexpected value past the end of the ROM")
+	}
+}
+
+func TestBusMemory(t *testing.T) {
+	m := bus.NewMap(nil, model.DMG)
+
+	m.Set16Bit(0x1234, 0xC000)
+	if m.GetByte(0xC000) != 0x34 || m.Get16Bit(0xC000) != 0x1234 {
+		t.Fatal("Unexpected value in WRAM")
+	}
+
+	m.SetByte(0xAB, 0xFFFF)
+	if m.GetByte(0xFFFF) != 0xAB {
+		t.Fatal("Unexpected value in IE")
+	}
+}
+
+func TestBusEcho(t *testing.T) {
+	m := bus.NewMap(nil, model.DMG)
+
+	m.SetByte(0x42, 0xE123)
+	if m.GetByte(0xC123) != 0x42 {
+		t.Fatal("Echo RAM write not mirrored into WRAM")
+	}
+
+	m.SetByte(0x24, 0xDDFF)
+	if m.GetByte(0xFDFF) != 0x24 {
+		t.Fatal("WRAM write not mirrored into echo RAM")
+	}
+}
+
+func TestBusUnusable(t *testing.T) {
+	for _, c := range []struct {
+		model    model.Model
+		expected byte
+	}{
+		{model.DMG, 0x00}, {model.MGB, 0x00}, {model.SGB, 0x00}, {model.CGB, 0x99}, {model.AGB, 0xBB},
+	} {
+		m := bus.NewMap(nil, c.model)
+		m.SetByte(0x99, 0xFEB5)
+		if m.GetByte(0xFEB5) != c.expected {
+			t.Fatalf("Unexpected value in the unusable area of %s", c.model)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/markelmencia/gogb/emulator"
+)
+
+func TestDMATransfer(t *testing.T) {
+	emu := getEmulation(t, getProgramROM(), emulator.Config{})
+	for i := range 0xA0 {
+		emu.RAM.SetByte(byte(i)^0x5A, 0xC100+uint16(i))
+	}
+
+	emu.Write(0xC1, 0xFF46)
+	if emu.RAM.GetByte(0xFF46) != 0xC1 {
+		t.Fatal("Unexpected DMA value")
+	}
+
+	// One M-cycle to start the transfer
+	emu.Clock.Tick()
+	if emu.DMAActive() {
+		t.Fatal("DMA started with no delay")
+	}
+
+	emu.Clock.Advance(0xA0)
+	for i := range 0xA0 {
+		if emu.RAM.GetByte(0xFE00+uint16(i)) != byte(i)^0x5A {
+			t.Fatalf("Unexpected value in OAM at 0x%04X", 0xFE00+i)
+		}
+	}
+
+	// The transfer blocks the bus for 160 M-cycles
+	if !emu.DMAActive() {
+		t.Fatal("DMA finished early")
+	}
+	emu.Clock.Tick()
+	if emu.DMAActive() {
+		t.Fatal("DMA did not finish")
+	}
+}
+
+func TestDMABusConflicts(t *testing.T) {
+	emu := getEmulation(t, getProgramROM(), emulator.Config{})
+	emu.RAM.SetByte(0x11, 0xC100)
+	emu.RAM.SetByte(0x33, 0xC200)
+	emu.RAM.SetByte(0x44, 0x8000)
+	emu.RAM.SetByte(0x55, 0xFF80)
+
+	emu.Write(0xC1, 0xFF46)
+	emu.Clock.Tick()
+
+	// The read happens on the M-cycle the
+	// first byte is copied
+	if emu.Read(0xC200) != 0x11 {
+		t.Fatal("Unexpected value read from the external bus")
+	}
+
+	if emu.Read(0xFE00) != 0xFF {
+		t.Fatal("Unexpected value read from OAM")
+	}
+
+	if emu.Read(0x8000) != 0x44 || emu.Read(0xFF80) != 0x55 {
+		t.Fatal("Unexpected value read from a free bus")
+	}
+
+	emu.Write(0x99, 0xC300)
+	if emu.RAM.GetByte(0xC300) != 0x00 {
+		t.Fatal("Write went through the external bus")
+	}
+}
+
+func TestDMARoutine(t *testing.T) {
+	emu := getEmulation(t, getProgramROM(), emulator.Config{})
+	routine := []byte{
+		0x3E, 0xC1, // 0xFF80: LD A, 0xC1
+		0xE0, 0x46, // 0xFF82: LDH (0x46), A
+		0x3E, 0x28, // 0xFF84: LD A, 40
+		0x3D,             // 0xFF86: DEC A
+		0xC2, 0x86, 0xFF, // 0xFF87: JP NZ, 0xFF86
+	}
+	for i, b := range routine {
+		emu.RAM.SetByte(b, 0xFF80+uint16(i))
+	}
+	for i := range 0xA0 {
+		emu.RAM.SetByte(byte(i), 0xC100+uint16(i))
+	}
+	emu.CPU.PC = 0xFF80
+
+	for emu.CPU.PC != 0xFF8A {
+		emu.Step()
+	}
+
+	for i := range 0xA0 {
+		if emu.RAM.GetByte(0xFE00+uint16(i)) != byte(i) {
+			t.Fatalf("Unexpected value in OAM at 0x%04X", 0xFE00+i)
+		}
+	}
+}
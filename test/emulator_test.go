@@ -0,0 +1,598 @@
+package test
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"testing"
+
+	"github.com/markelmencia/gogb/cpu"
+	"github.com/markelmencia/gogb/cpu/instructions"
+	"github.com/markelmencia/gogb/emulator"
+	"github.com/markelmencia/gogb/interrupts"
+	"github.com/markelmencia/gogb/ioreg"
+)
+
+// Returns a cartridge with the given
+// program at the entry point (0x0100).
+func getProgramROM(program ...byte) []byte {
+	rom := make([]byte, 0x8000)
+	copy(rom[0x0100:], program)
+	return rom
+}
+
+// Returns an emulation created from rom and cfg,
+// failing the test if it can not be created. No
+// interrupt is requested at the start, except with
+// EngineDifferential, whose interpreter copy can
+// only be changed through Step.
+func getEmulation(t *testing.T, rom []byte, cfg emulator.Config) *emulator.Emulation {
+	emu, err := emulator.New(rom, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Engine != emulator.EngineDifferential {
+		emu.RAM.SetByte(0x00, interrupts.AddrIF)
+	}
+	return emu
+}
+
+func TestDecodeTables(t *testing.T) {
+	// Opcodes without handlers: the 0xCB prefix
+	// and the 11 illegal opcodes
+	undefined := map[byte]bool{
+		0xCB: true, 0xD3: true, 0xDB: true, 0xDD: true, 0xE3: true, 0xE4: true, 0xEB: true,
+		0xEC: true, 0xED: true, 0xF4: true, 0xFC: true, 0xFD: true,
+	}
+
+	for op := range 256 {
+		if (emulator.Opcodes[op] == nil) != undefined[byte(op)] {
+			t.Fatalf("Unexpected handler for opcode 0x%02X", op)
+		}
+
+		if emulator.CBOpcodes[op] == nil {
+			t.Fatalf("Missing handler for opcode 0xCB 0x%02X", op)
+		}
+	}
+}
+
+func TestStep(t *testing.T) {
+	emu := getEmulation(t, getProgramROM(
+		0x3E, 0x42, // LD A, 0x42
+		0x47,       // LD B, A
+		0x04,       // INC B
+		0xCB, 0x37, // SWAP A
+		0x21, 0x00, 0xC0, // LD HL, 0xC000
+		0x70,             // LD (HL), B
+		0xC3, 0x50, 0x01, // JP 0x0150
+	), emulator.Config{})
+
+	emu.Step()
+	if emu.CPU.GetHalve(cpu.A) != 0x42 || emu.CPU.PC != 0x0102 {
+		t.Fatal("Unexpected state after LD A, n")
+	}
+
+	emu.Step()
+	emu.Step()
+	if emu.CPU.GetHalve(cpu.B) != 0x43 || emu.CPU.PC != 0x0104 {
+		t.Fatal("Unexpected state after INC B")
+	}
+
+	emu.Step()
+	if emu.CPU.GetHalve(cpu.A) != 0x24 || emu.CPU.PC != 0x0106 {
+		t.Fatal("Unexpected state after SWAP A")
+	}
+
+	emu.Step()
+	emu.Step()
+	if emu.RAM.GetByte(0xC000) != 0x43 {
+		t.Fatal("Unexpected memory value after LD (HL), B")
+	}
+
+	emu.Step()
+	if emu.CPU.PC != 0x0150 {
+		t.Fatal("Unexpected PC value after JP nn")
+	}
+}
+
+func TestStepRST(t *testing.T) {
+	emu := getEmulation(t, getProgramROM(0xEF), emulator.Config{}) // RST 0x28
+	emu.Step()
+
+	if emu.CPU.PC != 0x0028 {
+		t.Fatal("Unexpected PC value")
+	}
+}
+
+func TestStepSubroutines(t *testing.T) {
+	rom := make([]byte, 0x8000)
+	copy(rom[0x0100:], []byte{
+		0x01, 0x34, 0x12, // 0x0100: LD BC, 0x1234
+		0xCD, 0x10, 0x01, // 0x0103: CALL 0x0110
+		0xCD, 0x20, 0x01, // 0x0106: CALL 0x0120
+		0x76, // 0x0109: HALT
+	})
+	copy(rom[0x0110:], []byte{
+		0xC5,             // 0x0110: PUSH BC
+		0x01, 0x00, 0x00, // 0x0111: LD BC, 0x0000
+		0xC1, // 0x0114: POP BC
+		0xD5, // 0x0115: PUSH DE
+		0xE1, // 0x0116: POP HL
+		0xC9, // 0x0117: RET
+	})
+	copy(rom[0x0120:], []byte{
+		0xE1, // 0x0120: POP HL
+		0xE9, // 0x0121: JP HL
+	})
+	emu := getEmulation(t, rom, emulator.Config{})
+	sp := emu.CPU.SP
+
+	for range 8 {
+		emu.Step()
+	}
+	if emu.CPU.PC != 0x0106 || emu.CPU.SP != sp {
+		t.Fatal("Unexpected PC or SP value after RET")
+	}
+	if emu.CPU.BC != 0x1234 || emu.CPU.HL != emu.CPU.DE {
+		t.Fatal("Unexpected register values after PUSH and POP")
+	}
+
+	// The return address is popped like any other value
+	for range 3 {
+		emu.Step()
+	}
+	if emu.CPU.PC != 0x0109 || emu.CPU.HL != 0x0109 || emu.CPU.SP != sp {
+		t.Fatal("Unexpected state after popping the return address")
+	}
+}
+
+func TestStepBitOperands(t *testing.T) {
+	emu := getEmulation(t, getProgramROM(
+		0xCB, 0xD8, // SET 3, B
+		0xCB, 0xA8, // RES 5, B
+	), emulator.Config{})
+	emu.CPU.SetHalve(cpu.B, 0x20)
+
+	emu.Step()
+	emu.Step()
+	if emu.CPU.GetHalve(cpu.B) != 0x08 {
+		t.Fatal("Unexpected value in register B")
+	}
+}
+
+func TestStepCycles(t *testing.T) {
+	emu := getEmulation(t, getProgramROM(
+		0x06, 0x01, // LD B, 0x01 (2)
+		0x05,       // DEC B (1)
+		0x20, 0x10, // JR NZ, +0x10 (2, not taken)
+		0xCA, 0x08, 0x01, // JP Z, 0x0108 (4, taken)
+		0xCB, 0x46, // BIT 0, (HL) (3)
+		0xCD, 0x00, 0x02, // CALL 0x0200 (6)
+	), emulator.Config{})
+
+	expected := []int{2, 1, 2, 4, 3, 6}
+	total := 0
+	for i, m := range expected {
+		if got, _ := emu.Step(); got != m {
+			t.Fatalf("Unexpected cycles in instruction %d: %d", i, got)
+		}
+		total += m
+	}
+
+	if emu.Clock.Cycles != uint64(total*emulator.TCyclesPerMCycle) {
+		t.Fatal("Unexpected clock value")
+	}
+}
+
+func TestConditionalCycles(t *testing.T) {
+	emu := getExampleEmulation()
+	emu.CPU.SetFlag(true, cpu.FlagZ)
+	if instructions.JPccnn(cpu.CondZ, emu) != 4 {
+		t.Fatal("Unexpected cycles in taken JP cc, nn")
+	}
+	if instructions.JPccnn(cpu.CondNZ, emu) != 3 {
+		t.Fatal("Unexpected cycles in not taken JP cc, nn")
+	}
+
+	if instructions.CALLccnn(cpu.CondZ, emu) != 6 {
+		t.Fatal("Unexpected cycles in taken CALL cc, nn")
+	}
+	if instructions.CALLccnn(cpu.CondNZ, emu) != 3 {
+		t.Fatal("Unexpected cycles in not taken CALL cc, nn")
+	}
+
+	if instructions.RETcc(cpu.CondZ, emu) != 5 {
+		t.Fatal("Unexpected cycles in taken RET cc")
+	}
+	if instructions.RETcc(cpu.CondNZ, emu) != 2 {
+		t.Fatal("Unexpected cycles in not taken RET cc")
+	}
+}
+
+func TestNOP(t *testing.T) {
+	emu := getEmulation(t, getProgramROM(0x00), emulator.Config{})
+	af := emu.CPU.AF
+
+	m, _ := emu.Step()
+	if m != 1 || emu.CPU.PC != 0x0101 || emu.CPU.AF != af {
+		t.Fatal("Unexpected state after NOP")
+	}
+}
+
+func TestEIDelay(t *testing.T) {
+	emu := getEmulation(t, getProgramROM(
+		0xFB, // EI
+		0x00, // NOP
+		0x00, // NOP
+	), emulator.Config{})
+
+	emu.Step()
+	if emu.CPU.IME {
+		t.Fatal("IME was set by EI itself")
+	}
+
+	emu.Step()
+	if !emu.CPU.IME {
+		t.Fatal("IME was not set after the instruction following EI")
+	}
+}
+
+func TestDICancelsEI(t *testing.T) {
+	emu := getEmulation(t, getProgramROM(
+		0xFB, // EI
+		0xF3, // DI
+		0x00, // NOP
+	), emulator.Config{})
+
+	emu.Step()
+	emu.Step()
+	emu.Step()
+	if emu.CPU.IME {
+		t.Fatal("DI did not cancel EI")
+	}
+}
+
+func TestHALT(t *testing.T) {
+	emu := getEmulation(t, getProgramROM(
+		0x76,       // HALT
+		0x3E, 0x42, // LD A, 0x42
+	), emulator.Config{})
+	emu.RAM.SetByte(0x04, 0xFFFF) // IE: Timer
+
+	emu.Step()
+	if !emu.CPU.Halted || emu.CPU.PC != 0x0101 {
+		t.Fatal("CPU did not halt")
+	}
+
+	for range 10 {
+		if m, _ := emu.Step(); m != 1 {
+			t.Fatal("Unexpected cycles while halted")
+		}
+	}
+	if !emu.CPU.Halted || emu.CPU.PC != 0x0101 {
+		t.Fatal("CPU left halt without a pending interrupt")
+	}
+
+	// A disabled interrupt does not wake the CPU
+	emu.RAM.SetByte(0x01, 0xFF0F)
+	emu.Step()
+	if !emu.CPU.Halted {
+		t.Fatal("CPU left halt with a disabled interrupt")
+	}
+
+	emu.RAM.SetByte(0x05, 0xFF0F)
+	emu.Step()
+	if emu.CPU.Halted || emu.CPU.GetHalve(cpu.A) != 0x42 {
+		t.Fatal("CPU did not resume after HALT")
+	}
+}
+
+func TestHALTBug(t *testing.T) {
+	emu := getEmulation(t, getProgramROM(
+		0x76,       // HALT
+		0x3E, 0x42, // LD A, 0x42
+	), emulator.Config{})
+	emu.RAM.SetByte(0x04, 0xFFFF)
+	emu.RAM.SetByte(0x04, 0xFF0F)
+
+	emu.Step()
+	if emu.CPU.Halted {
+		t.Fatal("CPU halted with IME reset and a pending interrupt")
+	}
+
+	// 0x3E is read twice: once as the opcode and once
+	// as the operand, so 0x42 is executed as an opcode
+	emu.Step()
+	if emu.CPU.GetHalve(cpu.A) != 0x3E || emu.CPU.PC != 0x0102 {
+		t.Fatal("Unexpected state after the HALT bug")
+	}
+}
+
+func TestSTOP(t *testing.T) {
+	emu := getEmulation(t, getProgramROM(
+		0x10, 0x00, // STOP
+		0x3E, 0x42, // LD A, 0x42
+	), emulator.Config{})
+
+	emu.Step()
+	if !emu.CPU.Stopped || emu.CPU.PC != 0x0102 {
+		t.Fatal("CPU did not stop")
+	}
+
+	emu.Step()
+	if !emu.CPU.Stopped {
+		t.Fatal("CPU left STOP without joypad input")
+	}
+
+	emu.IO.Set(0xCE, ioreg.P1) // Right / A pressed
+	emu.Step()
+	if emu.CPU.Stopped || emu.CPU.GetHalve(cpu.A) != 0x42 {
+		t.Fatal("CPU did not resume after STOP")
+	}
+}
+
+func TestInterruptDispatch(t *testing.T) {
+	emu := getEmulation(t, getProgramROM(0x00), emulator.Config{}) // NOP
+	emu.CPU.IME = true
+	emu.RAM.SetByte(0x1F, interrupts.AddrIE)
+	emu.Interrupts.Request(interrupts.Timer)
+	sp := emu.CPU.SP
+
+	if m, _ := emu.Step(); m != 5 {
+		t.Fatal("Unexpected interrupt dispatch cycles")
+	}
+
+	if emu.CPU.PC != 0x0050 {
+		t.Fatal("Unexpected PC value")
+	}
+
+	if emu.CPU.SP != sp-2 || emu.RAM.Get16Bit(emu.CPU.SP) != 0x0100 {
+		t.Fatal("PC was not pushed into the stack")
+	}
+
+	if emu.CPU.IME {
+		t.Fatal("IME was not reset")
+	}
+
+	// The unused bits of IF read as 1
+	if emu.RAM.GetByte(interrupts.AddrIF) != 0xE0 {
+		t.Fatal("Interrupt was not acknowledged")
+	}
+}
+
+func TestInterruptPriority(t *testing.T) {
+	emu := getEmulation(t, getProgramROM(), emulator.Config{})
+	emu.CPU.IME = true
+	emu.RAM.SetByte(0x1E, interrupts.AddrIE) // VBlank disabled
+	emu.Interrupts.Request(interrupts.Joypad)
+	emu.Interrupts.Request(interrupts.Serial)
+	emu.Interrupts.Request(interrupts.VBlank)
+
+	emu.Step()
+	if emu.CPU.PC != 0x0058 {
+		t.Fatal("Unexpected PC value")
+	}
+
+	// IF keeps the interrupts that were not serviced
+	if emu.RAM.GetByte(interrupts.AddrIF) != 0xF1 {
+		t.Fatal("Unexpected IF value")
+	}
+}
+
+func TestInterruptIME(t *testing.T) {
+	emu := getEmulation(t, getProgramROM(0x00), emulator.Config{}) // NOP
+	emu.RAM.SetByte(0x01, interrupts.AddrIE)
+	emu.Interrupts.Request(interrupts.VBlank)
+
+	emu.Step()
+	if emu.CPU.PC != 0x0101 {
+		t.Fatal("Interrupt was serviced with IME reset")
+	}
+}
+
+func TestInterruptRETI(t *testing.T) {
+	rom := make([]byte, 0x8000)
+	rom[0x0060] = 0xD9 // RETI in the joypad handler
+	emu := getEmulation(t, rom, emulator.Config{})
+	emu.CPU.IME = true
+	emu.RAM.SetByte(0x10, interrupts.AddrIE)
+	emu.Interrupts.Request(interrupts.Joypad)
+
+	emu.Step()
+	emu.Step()
+	if emu.CPU.PC != 0x0100 || !emu.CPU.IME {
+		t.Fatal("Unexpected state after returning from the handler")
+	}
+}
+
+func TestInterruptWakesHALT(t *testing.T) {
+	emu := getEmulation(t, getProgramROM(0x76), emulator.Config{}) // HALT
+	emu.CPU.IME = true
+	emu.RAM.SetByte(0x01, interrupts.AddrIE)
+
+	emu.Step()
+	emu.Step()
+	if !emu.CPU.Halted {
+		t.Fatal("CPU did not halt")
+	}
+
+	emu.Interrupts.Request(interrupts.VBlank)
+	emu.Step()
+	if emu.CPU.Halted || emu.CPU.PC != 0x0040 {
+		t.Fatal("Interrupt was not serviced after HALT")
+	}
+
+	if emu.RAM.Get16Bit(emu.CPU.SP) != 0x0101 {
+		t.Fatal("Unexpected return address")
+	}
+}
+
+func TestIllegalOpcodeLockUp(t *testing.T) {
+	emu := getEmulation(t, getProgramROM(0xD3), emulator.Config{})
+	emu.RAM.SetByte(0x01, interrupts.AddrIE)
+	emu.CPU.IME = true
+
+	for range 3 {
+		if _, err := emu.Step(); err != nil {
+			t.Fatal("Unexpected error when locking up")
+		}
+	}
+
+	// Not even interrupts bring the CPU back
+	emu.Interrupts.Request(interrupts.VBlank)
+	emu.Step()
+	if !emu.CPU.Locked || emu.CPU.PC != 0x0100 {
+		t.Fatal("CPU did not lock up")
+	}
+}
+
+func TestIllegalOpcodeStop(t *testing.T) {
+	emu := getEmulation(t, getProgramROM(0x00, 0xFD), emulator.Config{})
+	emu.FaultPolicy = emulator.FaultStop
+
+	emu.Step()
+	_, err := emu.Step()
+
+	var illegal *emulator.IllegalOpcodeError
+	if !errors.As(err, &illegal) {
+		t.Fatal("Unexpected error type")
+	}
+
+	if illegal.Opcode != 0xFD || illegal.PC != 0x0101 {
+		t.Fatal("Unexpected error fields")
+	}
+
+	// The emulation does not go any further
+	if _, err := emu.Step(); err == nil || emu.CPU.PC != 0x0101 {
+		t.Fatal("Emulation continued after an illegal opcode")
+	}
+}
+
+func TestIllegalOpcodeLog(t *testing.T) {
+	emu := getEmulation(t, getProgramROM(0xE4, 0x3E, 0x42), emulator.Config{}) // ILLEGAL, LD A, 0x42
+	var out bytes.Buffer
+	emu.FaultPolicy = emulator.FaultLog
+	emu.Logger = log.New(&out, "", 0)
+
+	if _, err := emu.Step(); err != nil {
+		t.Fatal("Unexpected error")
+	}
+
+	if out.Len() == 0 {
+		t.Fatal("Fault was not logged")
+	}
+
+	emu.Step()
+	if emu.CPU.GetHalve(cpu.A) != 0x42 {
+		t.Fatal("Emulation did not continue after the illegal opcode")
+	}
+}
+
+func TestStackWraparound(t *testing.T) {
+	emu := getEmulation(t, getProgramROM(
+		0xC5, // PUSH BC
+		0xC1, // POP BC
+	), emulator.Config{})
+	emu.FaultPolicy = emulator.FaultStop
+	emu.CPU.SP = 0x0001
+
+	_, err := emu.Step()
+	var wrap *emulator.StackWraparoundError
+	if !errors.As(err, &wrap) || wrap.From != 0x0001 || wrap.To != 0xFFFF {
+		t.Fatal("Push wraparound was not detected")
+	}
+
+	if _, err := emu.Step(); !errors.As(err, &wrap) {
+		t.Fatal("Pop wraparound was not detected")
+	}
+}
+
+func TestUnmappedExecution(t *testing.T) {
+	emu := getEmulation(t, getProgramROM(), emulator.Config{})
+	emu.FaultPolicy = emulator.FaultStop
+	emu.CPU.PC = 0xFEA0
+
+	_, err := emu.Step()
+	var unmapped *emulator.UnmappedExecutionError
+	if !errors.As(err, &unmapped) || unmapped.PC != 0xFEA0 {
+		t.Fatal("Unexpected error")
+	}
+}
+
+// Component that calls a function on every M-cycle,
+// numbering them from 1.
+type probe struct {
+	cycle  int
+	onTick func(cycle int)
+}
+
+func (p *probe) Tick() {
+	p.cycle++
+	p.onTick(p.cycle)
+}
+
+func TestMCycleReadTiming(t *testing.T) {
+	// LD A, (0xC000): the memory operand is read on M4
+	for _, c := range []struct {
+		writeCycle int
+		expected   byte
+	}{{4, 0x99}, {5, 0x11}} {
+		emu := getEmulation(t, getProgramROM(0xFA, 0x00, 0xC0, 0x00), emulator.Config{})
+		emu.RAM.SetByte(0x11, 0xC000)
+		emu.Clock.Attach(&probe{onTick: func(cycle int) {
+			if cycle == c.writeCycle {
+				emu.RAM.SetByte(0x99, 0xC000)
+			}
+		}})
+
+		emu.Step()
+		if emu.CPU.GetHalve(cpu.A) != c.expected {
+			t.Fatalf("Unexpected value read with a write on M%d", c.writeCycle)
+		}
+	}
+}
+
+func TestMCycleWriteTiming(t *testing.T) {
+	// PUSH BC: internal M2, high byte written on M3
+	// and low byte written on M4
+	emu := getEmulation(t, getProgramROM(0xC5), emulator.Config{})
+	emu.CPU.BC = 0x1234
+	emu.CPU.SP = 0xD000
+
+	var seen []uint16
+	emu.Clock.Attach(&probe{onTick: func(int) {
+		seen = append(seen, emu.RAM.Get16Bit(0xCFFE))
+	}})
+
+	emu.Step()
+	expected := []uint16{0x0000, 0x0000, 0x0000, 0x1200}
+	for i := range expected {
+		if seen[i] != expected[i] {
+			t.Fatalf("Unexpected memory value at the start of M%d", i+1)
+		}
+	}
+
+	if emu.RAM.Get16Bit(0xCFFE) != 0x1234 || emu.CPU.SP != 0xCFFE {
+		t.Fatal("Unexpected memory value after PUSH")
+	}
+}
+
+func TestMCycleTicks(t *testing.T) {
+	rom := make([]byte, 0x8000)
+	copy(rom[0x0100:], []byte{0xCD, 0x00, 0x02}) // CALL 0x0200 (6)
+	rom[0x0200] = 0xC9                           // RET (4)
+	emu := getEmulation(t, rom, emulator.Config{})
+
+	ticks := 0
+	emu.Clock.Attach(&probe{onTick: func(int) { ticks++ }})
+
+	emu.Step()
+	if ticks != 6 {
+		t.Fatal("Unexpected M-cycles in CALL")
+	}
+
+	emu.Step()
+	if ticks != 10 {
+		t.Fatal("Unexpected M-cycles in RET")
+	}
+}
@@ -0,0 +1,112 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/markelmencia/gogb/cartridge"
+)
+
+func TestParseHeader(t *testing.T) {
+	rom := getHeaderROM("TETRIS", 0x00, 0x01, 0x00)
+	rom[0x0147] = 0x03
+	rom[0x0148] = 0x02
+	rom[0x0149] = 0x03
+	rom[0x014C] = 0x01
+	rom[0x014D] = cartridge.GetCartHDChecksum(rom)
+
+	h, err := cartridge.ParseHeader(rom)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if h.Title != "TETRIS" || h.ManufacturerCode != "" || h.Version != 0x01 {
+		t.Fatal("Unexpected title, manufacturer code or version")
+	}
+	if h.Type != 0x03 || h.TypeName != "MBC1+RAM+BATTERY" {
+		t.Fatal("Unexpected cartridge type")
+	}
+	if h.ROMSizeCode != 0x02 || h.RAMSizeCode != 0x03 || h.ROMSize != 128*1024 || h.RAMSize != 32*1024 {
+		t.Fatal("Unexpected ROM or RAM size")
+	}
+	if h.Licensee != "Nintendo" {
+		t.Fatal("Unexpected licensee")
+	}
+	if !h.HeaderChecksumMatches() || h.GlobalChecksumMatches() {
+		t.Fatal("Unexpected checksum check")
+	}
+}
+
+func TestParseHeaderTitle(t *testing.T) {
+	for _, c := range []struct {
+		title        string
+		cgbFlag      byte
+		expected     string
+		manufacturer string
+	}{
+		{"SIXTEEN CHARS 16", '6', "SIXTEEN CHARS 16", ""},
+		{"FIFTEEN CHARS 1", 0x80, "FIFTEEN CHARS 1", ""},
+		{"POKEMON YELLOW", 0x80, "POKEMON YELLOW", ""},
+		{"PM_CRYSTAL\x00BYTE", 0xC0, "PM_CRYSTAL", "BYTE"},
+		{"ELEVENCHARSAAXE", 0x80, "ELEVENCHARS", "AAXE"},
+	} {
+		h, err := cartridge.ParseHeader(getHeaderROM(c.title, c.cgbFlag, 0x01, 0x00))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if h.Title != c.expected || h.ManufacturerCode != c.manufacturer {
+			t.Fatalf("Unexpected title %q for %q", h.Title, c.title)
+		}
+	}
+}
+
+func TestParseHeaderLicensee(t *testing.T) {
+	rom := getHeaderROM("GAME", 0x00, 0x33, 0x00)
+	copy(rom[0x0144:0x0146], "01")
+
+	h, _ := cartridge.ParseHeader(rom)
+	if h.NewLicenseeCode != "01" || !strings.HasPrefix(h.Licensee, "Nintendo R") {
+		t.Fatal("New licensee code not used")
+	}
+}
+
+func TestParseHeaderTooSmall(t *testing.T) {
+	if _, err := cartridge.ParseHeader(make([]byte, 0x14F)); err == nil {
+		t.Fatal("Expected an error")
+	}
+}
+
+func TestHeaderJSON(t *testing.T) {
+	h, _ := cartridge.ParseHeader(getHeaderROM("GAME", 0x80, 0x01, 0x00))
+
+	var buf bytes.Buffer
+	if err := h.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded cartridge.Header
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded != h {
+		t.Fatal("Unexpected decoded header")
+	}
+}
+
+func TestHeaderPrint(t *testing.T) {
+	h, _ := cartridge.ParseHeader(getHeaderROM("GAME", 0x00, 0x01, 0x00))
+
+	var buf bytes.Buffer
+	h.Print(&buf, nil)
+	if !strings.Contains(buf.String(), "- ROM title: GAME\n") {
+		t.Fatal("Unexpected title line")
+	}
+	if !strings.Contains(buf.String(), "- Entry point instructions: 0x0 0x0 0x0 0x0\n") {
+		t.Fatal("Unexpected entry point line")
+	}
+	if !strings.Contains(buf.String(), "- ROM size code: 0x0 (32 KiB)\n") {
+		t.Fatal("Unexpected ROM size line")
+	}
+}
@@ -0,0 +1,84 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/markelmencia/gogb/cpu"
+	"github.com/markelmencia/gogb/emulator"
+	"github.com/markelmencia/gogb/interrupts"
+)
+
+func TestInstructionHooks(t *testing.T) {
+	emu := getEmulation(t, getProgramROM(
+		0x3E, 0x42, // LD A, 0x42
+		0xCB, 0x37, // SWAP A
+	), emulator.Config{})
+
+	var before, after []emulator.InstructionEvent
+	emu.AddHooks(emulator.Hooks{
+		BeforeInstruction: func(ev emulator.InstructionEvent) { before = append(before, ev) },
+		AfterInstruction:  func(ev emulator.InstructionEvent) { after = append(after, ev) },
+	})
+
+	emu.Step()
+	emu.Step()
+
+	if len(before) != 2 || len(after) != 2 {
+		t.Fatal("Unexpected number of events")
+	}
+
+	if before[0].PC != 0x0100 || before[0].Opcode != 0x3E || before[0].CB ||
+		before[0].CPU.GetHalve(cpu.A) != 0x01 || before[0].Cycles != 0 {
+		t.Fatal("Unexpected event before LD A, n")
+	}
+
+	if after[0].CPU.GetHalve(cpu.A) != 0x42 || after[0].CPU.PC != 0x0102 || after[0].Cycles != 2 {
+		t.Fatal("Unexpected event after LD A, n")
+	}
+
+	if before[1].PC != 0x0102 || before[1].Opcode != 0x37 || !before[1].CB ||
+		after[1].CPU.GetHalve(cpu.A) != 0x24 {
+		t.Fatal("Unexpected events for SWAP A")
+	}
+}
+
+func TestInterruptHook(t *testing.T) {
+	emu := getEmulation(t, getProgramROM(0x00), emulator.Config{}) // NOP
+	emu.CPU.IME = true
+	emu.Interrupts.Memory.SetByte(0x04, interrupts.AddrIE)
+	emu.Interrupts.Request(interrupts.Timer)
+
+	var events []emulator.InterruptEvent
+	instructions := 0
+	emu.AddHooks(emulator.Hooks{
+		AfterInstruction: func(emulator.InstructionEvent) { instructions++ },
+		Interrupt:        func(ev emulator.InterruptEvent) { events = append(events, ev) },
+	})
+
+	emu.Step()
+
+	if len(events) != 1 || instructions != 0 {
+		t.Fatal("Unexpected number of events")
+	}
+
+	ev := events[0]
+	if ev.Interrupt != interrupts.Timer || ev.PC != 0x0100 || ev.CPU.PC != 0x0050 || ev.Cycles != 5 {
+		t.Fatal("Unexpected interrupt event")
+	}
+}
+
+func TestHooksOrder(t *testing.T) {
+	emu := getEmulation(t, getProgramROM(0x00), emulator.Config{}) // NOP
+
+	var order []int
+	for i := range 3 {
+		emu.AddHooks(emulator.Hooks{
+			BeforeInstruction: func(emulator.InstructionEvent) { order = append(order, i) },
+		})
+	}
+
+	emu.Step()
+	if len(order) != 3 || order[0] != 0 || order[1] != 1 || order[2] != 2 {
+		t.Fatal("Unexpected hook order")
+	}
+}
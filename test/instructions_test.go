@@ -332,7 +332,7 @@ func TestPUSHrr(t *testing.T) {
 	emu := getExampleEmulation()
 	emu.CPU.SetReg(cpu.DE, 0x1289)
 	instructions.PUSHrr(cpu.DE, emu)
-	if emu.RAM.Get16Bit(0xFADE) != 0x1289 {
+	if emu.RAM.Get16Bit(0xFADC) != 0x1289 {
 		t.Fatal("Unexpected memory value")
 	}
 
@@ -362,6 +362,41 @@ func TestPOPrr(t *testing.T) {
 	}
 }
 
+func TestPUSHPOPRoundTrip(t *testing.T) {
+	emu := getExampleEmulation()
+	sp := emu.CPU.SP
+	emu.CPU.SetReg(cpu.BC, 0x1234)
+	emu.CPU.SetReg(cpu.DE, 0x0000)
+
+	instructions.PUSHrr(cpu.BC, emu)
+	instructions.POPrr(cpu.DE, emu)
+	if emu.CPU.GetReg(cpu.DE) != 0x1234 {
+		t.Fatal("Unexpected register value in DE")
+	}
+
+	if emu.CPU.SP != sp {
+		t.Fatal("Unexpected register value in SP")
+	}
+}
+
+func TestPUSHHighByteFirst(t *testing.T) {
+	emu := getExampleEmulation()
+	emu.CPU.SetReg(cpu.SP, 0xFFFE)
+	emu.RAM.SetByte(0x1F, 0xFFFF)
+	emu.CPU.SetReg(cpu.HL, 0xABCD)
+
+	// The high byte goes below the old SP, so IE
+	// at 0xFFFF is left alone
+	instructions.PUSHrr(cpu.HL, emu)
+	if emu.RAM.GetByte(0xFFFD) != 0xAB || emu.RAM.GetByte(0xFFFC) != 0xCD {
+		t.Fatal("Unexpected memory value")
+	}
+
+	if emu.RAM.GetByte(0xFFFF) != 0x1F {
+		t.Fatal("IE was overwritten")
+	}
+}
+
 func TestLDHLSPpe(t *testing.T) {
 	emu := getExampleEmulation()
 	emu.RAM.SetByte(0xFE, 0x0001) // -2
@@ -413,7 +448,7 @@ func TestADDr(t *testing.T) {
 
 func TestADDHL(t *testing.T) {
 	emu := getExampleEmulation()
-	emu.RAM[emu.CPU.GetReg(cpu.HL)] = 0xAD
+	emu.RAM.SetByte(0xAD, emu.CPU.GetReg(cpu.HL))
 	vA := emu.CPU.GetHalve(cpu.A)
 
 	instructions.ADDHL(emu)
@@ -437,7 +472,7 @@ func TestADDHL(t *testing.T) {
 
 func TestADDn(t *testing.T) {
 	emu := getExampleEmulation()
-	emu.RAM[1] = 0xAD
+	emu.RAM.SetByte(0xAD, 1)
 	vA := emu.CPU.GetHalve(cpu.A)
 
 	instructions.ADDn(emu)
@@ -487,7 +522,7 @@ func TestADCr(t *testing.T) {
 func TestADCHL(t *testing.T) {
 	emu := getExampleEmulation()
 	emu.CPU.SetFlag(true, cpu.FlagC)
-	emu.RAM[emu.CPU.GetReg(cpu.HL)] = 0xAD
+	emu.RAM.SetByte(0xAD, emu.CPU.GetReg(cpu.HL))
 	vA := emu.CPU.GetHalve(cpu.A)
 
 	instructions.ADCHL(emu)
@@ -512,7 +547,7 @@ func TestADCHL(t *testing.T) {
 func TestADCn(t *testing.T) {
 	emu := getExampleEmulation()
 	emu.CPU.SetFlag(true, cpu.FlagC)
-	emu.RAM[0x01] = 0xAD
+	emu.RAM.SetByte(0xAD, 0x01)
 	vA := emu.CPU.GetHalve(cpu.A)
 
 	instructions.ADCn(emu)
@@ -1064,16 +1099,16 @@ func TestCCF(t *testing.T) {
 	c := emu.CPU.IsFlag(cpu.FlagC)
 	instructions.CCF(emu)
 
-	if emu.CPU.IsFlag(cpu.FlagC) == !c {
+	if emu.CPU.IsFlag(cpu.FlagC) == c {
 		t.Fatal("Unexpected value in flag C")
 	}
 
 	if emu.CPU.IsFlag(cpu.FlagN) {
-		t.Fatal("Unexpected value in flag Z")
+		t.Fatal("Unexpected value in flag N")
 	}
 
 	if emu.CPU.IsFlag(cpu.FlagH) {
-		t.Fatal("Unexpected value in flag N")
+		t.Fatal("Unexpected value in flag H")
 	}
 
 	if emu.CPU.PC != 1 {
@@ -1817,7 +1852,7 @@ func TestSRLHL(t *testing.T) {
 func TestBITbr(t *testing.T) {
 	emu := getExampleEmulation()
 	instructions.BITbr(5, cpu.L, emu)
-	if !emu.CPU.IsFlag(cpu.FlagZ) {
+	if emu.CPU.IsFlag(cpu.FlagZ) {
 		t.Fatal("Unexpected flag Z value")
 	}
 
@@ -1830,7 +1865,7 @@ func TestBITbr(t *testing.T) {
 	}
 
 	instructions.BITbr(4, cpu.L, emu)
-	if emu.CPU.IsFlag(cpu.FlagZ) {
+	if !emu.CPU.IsFlag(cpu.FlagZ) {
 		t.Fatal("Unexpected flag Z value")
 	}
 
@@ -1851,7 +1886,7 @@ func TestBITbHL(t *testing.T) {
 	emu := getExampleEmulation()
 	emu.CPU.SetReg(cpu.HL, 0x0001)
 	instructions.BITbHL(6, emu)
-	if emu.CPU.IsFlag(cpu.FlagZ) {
+	if !emu.CPU.IsFlag(cpu.FlagZ) {
 		t.Fatal("Unexpected flag Z value")
 	}
 
@@ -1864,7 +1899,7 @@ func TestBITbHL(t *testing.T) {
 	}
 
 	instructions.BITbHL(7, emu)
-	if !emu.CPU.IsFlag(cpu.FlagZ) {
+	if emu.CPU.IsFlag(cpu.FlagZ) {
 		t.Fatal("Unexpected flag Z value")
 	}
 
@@ -1997,9 +2032,10 @@ func TestJRcce(t *testing.T) {
 
 func TestCALLnn(t *testing.T) {
 	emu := getExampleEmulation()
+	sp := emu.CPU.SP
 	instructions.CALLnn(emu)
 
-	if emu.RAM.Get16Bit(emu.CPU.GetReg(cpu.SP)+1) != 0x0003 {
+	if emu.CPU.SP != sp-2 || emu.RAM.Get16Bit(sp-2) != 0x0003 {
 		t.Fatal("Unexpected value in register SP")
 	}
 
@@ -2010,10 +2046,11 @@ func TestCALLnn(t *testing.T) {
 
 func TestCALLccnn(t *testing.T) {
 	emu := getExampleEmulation()
+	sp := emu.CPU.SP
 	emu.CPU.SetFlag(false, cpu.FlagC)
 	instructions.CALLccnn(cpu.CondNC, emu)
 
-	if emu.RAM.Get16Bit(emu.CPU.GetReg(cpu.SP)+1) != 0x0003 {
+	if emu.CPU.SP != sp-2 || emu.RAM.Get16Bit(sp-2) != 0x0003 {
 		t.Fatal("Unexpected value in register SP")
 	}
 
@@ -2037,6 +2074,27 @@ func TestRET(t *testing.T) {
 	}
 }
 
+func TestCALLPUSHPOPRET(t *testing.T) {
+	emu := getExampleEmulation()
+	sp := emu.CPU.SP
+	emu.CPU.SetReg(cpu.BC, 0x5678)
+
+	// CALL nn returns to the instruction after it
+	instructions.CALLnn(emu)
+	instructions.PUSHrr(cpu.BC, emu)
+	emu.CPU.SetReg(cpu.BC, 0x0000)
+	instructions.POPrr(cpu.BC, emu)
+	instructions.RET(emu)
+
+	if emu.CPU.GetReg(cpu.PC) != 0x0003 {
+		t.Fatal("Unexpected PC value")
+	}
+
+	if emu.CPU.GetReg(cpu.BC) != 0x5678 || emu.CPU.SP != sp {
+		t.Fatal("Unexpected register values")
+	}
+}
+
 func TestRETcc(t *testing.T) {
 	emu := getExampleEmulation()
 	emu.CPU.SetFlag(true, cpu.FlagZ)
@@ -2064,13 +2122,123 @@ func TestRETI(t *testing.T) {
 
 func TestRSTn(t *testing.T) {
 	emu := getExampleEmulation()
-	instructions.RSTn(emu)
+	sp := emu.CPU.SP
+	instructions.RSTn(0x38, emu)
 
-	if emu.CPU.GetReg(cpu.PC) != 0x0093 {
+	if emu.CPU.GetReg(cpu.PC) != 0x0038 {
 		t.Fatal("Unexpectedd PC value")
 	}
 
-	if emu.RAM.Get16Bit(emu.CPU.GetReg(cpu.SP)+1) != 0x0001 {
+	if emu.CPU.SP != sp-2 || emu.RAM.Get16Bit(sp-2) != 0x0001 {
 		t.Fatal("Unexpected value in register SP")
 	}
 }
+
+// Runs handler on an example emulation with r holding v
+// and F holding f, failing the test unless F ends as want.
+func checkFlags(t *testing.T, handler func(emulator.Emulation) int, r cpu.Halve, v, f, want byte) {
+	emu := getExampleEmulation()
+	emu.CPU.SetHalve(r, v)
+	emu.CPU.SetHalve(cpu.F, f)
+	handler(emu)
+
+	if emu.CPU.GetHalve(cpu.F) != want {
+		t.Fatalf("Unexpected flags 0x%02X", emu.CPU.GetHalve(cpu.F))
+	}
+}
+
+func TestPOPAF(t *testing.T) {
+	emu := getExampleEmulation()
+	emu.RAM.Set16Bit(0x12FF, emu.CPU.GetReg(cpu.SP))
+	instructions.POPrr(cpu.AF, emu)
+
+	// The low nibble of F can not be set
+	if emu.CPU.GetReg(cpu.AF) != 0x12F0 {
+		t.Fatal("Unexpected register value in AF")
+	}
+}
+
+func TestCCFFlags(t *testing.T) {
+	checkFlags(t, instructions.CCF, cpu.A, 0x00, 0x70, 0x00)
+	checkFlags(t, instructions.CCF, cpu.A, 0x00, 0x80, 0x90)
+}
+
+func TestRLCAFlags(t *testing.T) {
+	// Z is reset even if the result is zero
+	checkFlags(t, instructions.RLCA, cpu.A, 0x00, 0xE0, 0x00)
+	checkFlags(t, instructions.RLCA, cpu.A, 0x80, 0x00, 0x10)
+}
+
+func TestRRCAFlags(t *testing.T) {
+	checkFlags(t, instructions.RRCA, cpu.A, 0x00, 0xE0, 0x00)
+	checkFlags(t, instructions.RRCA, cpu.A, 0x01, 0x00, 0x10)
+}
+
+func TestRLAFlags(t *testing.T) {
+	checkFlags(t, instructions.RLA, cpu.A, 0x80, 0xE0, 0x10)
+}
+
+func TestRRAFlags(t *testing.T) {
+	checkFlags(t, instructions.RRA, cpu.A, 0x01, 0xE0, 0x10)
+}
+
+func TestRLCrFlags(t *testing.T) {
+	rlc := func(emu emulator.Emulation) int { return instructions.RLCr(cpu.B, emu) }
+	checkFlags(t, rlc, cpu.B, 0x00, 0x70, 0x80)
+	// A stale Z is cleared
+	checkFlags(t, rlc, cpu.B, 0x80, 0xE0, 0x10)
+}
+
+func TestRLCHLFlags(t *testing.T) {
+	emu := getExampleEmulation()
+	emu.CPU.SetReg(cpu.HL, 0xC000)
+	emu.RAM.SetByte(0x00, 0xC000)
+	emu.CPU.SetHalve(cpu.F, 0x70)
+	instructions.RLCHL(emu)
+
+	if emu.CPU.GetHalve(cpu.F) != 0x80 {
+		t.Fatal("Unexpected flags")
+	}
+}
+
+func TestRRCrFlags(t *testing.T) {
+	rrc := func(emu emulator.Emulation) int { return instructions.RRCr(cpu.B, emu) }
+	checkFlags(t, rrc, cpu.B, 0x00, 0x70, 0x80)
+	checkFlags(t, rrc, cpu.B, 0x01, 0xE0, 0x10)
+}
+
+func TestRLrFlags(t *testing.T) {
+	rl := func(emu emulator.Emulation) int { return instructions.RLr(cpu.B, emu) }
+	checkFlags(t, rl, cpu.B, 0x80, 0x60, 0x90)
+	checkFlags(t, rl, cpu.B, 0x01, 0xE0, 0x00)
+}
+
+func TestRRrFlags(t *testing.T) {
+	rr := func(emu emulator.Emulation) int { return instructions.RRr(cpu.B, emu) }
+	checkFlags(t, rr, cpu.B, 0x01, 0x60, 0x90)
+	checkFlags(t, rr, cpu.B, 0x02, 0xE0, 0x00)
+}
+
+func TestSLArFlags(t *testing.T) {
+	sla := func(emu emulator.Emulation) int { return instructions.SLAr(cpu.B, emu) }
+	checkFlags(t, sla, cpu.B, 0x80, 0x60, 0x90)
+	checkFlags(t, sla, cpu.B, 0x01, 0xE0, 0x00)
+}
+
+func TestSRArFlags(t *testing.T) {
+	sra := func(emu emulator.Emulation) int { return instructions.SRAr(cpu.B, emu) }
+	checkFlags(t, sra, cpu.B, 0x01, 0x60, 0x90)
+	checkFlags(t, sra, cpu.B, 0x80, 0xE0, 0x00)
+}
+
+func TestSRLrFlags(t *testing.T) {
+	srl := func(emu emulator.Emulation) int { return instructions.SRLr(cpu.B, emu) }
+	checkFlags(t, srl, cpu.B, 0x01, 0x60, 0x90)
+	checkFlags(t, srl, cpu.B, 0x80, 0xE0, 0x00)
+}
+
+func TestSWAPrFlags(t *testing.T) {
+	swap := func(emu emulator.Emulation) int { return instructions.SWAPr(cpu.B, emu) }
+	checkFlags(t, swap, cpu.B, 0x00, 0x70, 0x80)
+	checkFlags(t, swap, cpu.B, 0x12, 0xF0, 0x00)
+}
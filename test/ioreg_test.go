@@ -0,0 +1,82 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/markelmencia/gogb/emulator"
+	"github.com/markelmencia/gogb/ioreg"
+	"github.com/markelmencia/gogb/model"
+)
+
+func TestIORegisterUnmapped(t *testing.T) {
+	f := ioreg.NewFile(model.DMG, false)
+	for _, a := range []uint16{0xFF03, 0xFF15, 0xFF4D, 0xFF7F} {
+		f.SetByte(0x00, a)
+		if f.GetByte(a) != 0xFF {
+			t.Fatalf("Unexpected value at 0x%04X", a)
+		}
+	}
+}
+
+func TestIORegisterMasks(t *testing.T) {
+	f := ioreg.NewFile(model.DMG, false)
+
+	f.Set(0x02, ioreg.STAT)
+	f.SetByte(0x7F, ioreg.STAT)
+	if f.GetByte(ioreg.STAT) != 0xFA {
+		t.Fatal("Unexpected STAT value")
+	}
+
+	f.SetByte(0x00, ioreg.NR13)
+	if f.GetByte(ioreg.NR13) != 0xFF {
+		t.Fatal("Unexpected NR13 value")
+	}
+
+	f.SetByte(0x12, ioreg.LY)
+	if f.GetByte(ioreg.LY) != 0x00 {
+		t.Fatal("LY was written")
+	}
+
+	f.SetByte(0x00, ioreg.TAC)
+	if f.GetByte(ioreg.TAC) != 0xF8 {
+		t.Fatal("Unexpected TAC value")
+	}
+}
+
+func TestIORegisterModels(t *testing.T) {
+	for _, c := range []struct {
+		model    model.Model
+		cgbMode  bool
+		expected byte
+	}{
+		{model.DMG, false, 0xFF}, {model.CGB, false, 0xFF}, {model.CGB, true, 0xFE},
+	} {
+		f := ioreg.NewFile(c.model, c.cgbMode)
+		if f.GetByte(ioreg.VBK) != c.expected {
+			t.Fatalf("Unexpected VBK value on %s", c.model)
+		}
+	}
+
+	if _, ok := ioreg.Lookup(ioreg.OPRI, model.CGB, false); !ok {
+		t.Fatal("OPRI missing in DMG compatibility mode")
+	}
+
+	r, ok := ioreg.Lookup(ioreg.SC, model.CGB, true)
+	if !ok || r.Name != "SC" || r.Write != 0x83 {
+		t.Fatal("Unexpected SC register in CGB mode")
+	}
+}
+
+func TestIORegistersOnBus(t *testing.T) {
+	emu := getEmulation(t, getProgramROM(), emulator.Config{})
+
+	// Only the power bit of NR52 is writable
+	emu.Write(0x00, ioreg.NR52)
+	if emu.Read(ioreg.NR52) != 0x71 {
+		t.Fatal("Unexpected NR52 value")
+	}
+
+	if emu.Read(0xFF4C) != 0xFF {
+		t.Fatal("Unexpected value in an unmapped register")
+	}
+}
@@ -0,0 +1,400 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/markelmencia/gogb/emulator"
+	"github.com/markelmencia/gogb/mbc"
+)
+
+// Returns a ROM of the given cartridge type with
+// romBanks banks, each of which holds its bank
+// number in its first two bytes (low byte first),
+// and the RAM size code ramCode.
+func getMBCROM(cartType byte, romBanks int, ramCode byte) []byte {
+	rom := make([]byte, romBanks*mbc.ROMBankSize)
+	for i := range romBanks {
+		rom[i*mbc.ROMBankSize] = byte(i)
+		rom[i*mbc.ROMBankSize+1] = byte(i >> 8)
+	}
+	rom[0x0147] = cartType
+	rom[0x0149] = ramCode
+	return rom
+}
+
+// Logo the boot ROM checks in the cartridge header.
+var nintendoLogo = []byte{
+	0xCE, 0xED, 0x66, 0x66, 0xCC, 0x0D, 0x00, 0x0B, 0x03, 0x73, 0x00, 0x83,
+	0x00, 0x0C, 0x00, 0x0D, 0x00, 0x08, 0x11, 0x1F, 0x88, 0x89, 0x00, 0x0E,
+	0xDC, 0xCC, 0x6E, 0xE6, 0xDD, 0xDD, 0xD9, 0x99, 0xBB, 0xBB, 0x67, 0x63,
+	0x6E, 0x0E, 0xEC, 0xCC, 0xDD, 0xDC, 0x99, 0x9F, 0xBB, 0xB9, 0x33, 0x3E,
+}
+
+// Returns the numbers of the banks mapped
+// at 0x0000 and 0x4000.
+func mappedBanks(c mbc.MBC) (byte, byte) {
+	return c.GetByte(0x0000), c.GetByte(0x4000)
+}
+
+func TestMBCNone(t *testing.T) {
+	for _, cartType := range []byte{0x00, 0x08, 0xFC} {
+		if mbc.New(getMBCROM(cartType, 2, 0x00)) != nil {
+			t.Fatalf("Unexpected controller for type 0x%02X", cartType)
+		}
+	}
+}
+
+func TestMBC1ROMBanking(t *testing.T) {
+	c := mbc.New(getMBCROM(0x01, 128, 0x00))
+
+	if lo, hi := mappedBanks(c); lo != 0 || hi != 1 {
+		t.Fatal("Unexpected banks at power-on")
+	}
+
+	c.SetByte(0x05, 0x2000)
+	if _, hi := mappedBanks(c); hi != 5 {
+		t.Fatal("Unexpected bank 5")
+	}
+
+	// Bank 0 selects bank 1
+	c.SetByte(0x00, 0x2000)
+	if _, hi := mappedBanks(c); hi != 1 {
+		t.Fatal("Bank 0 mapped at 0x4000")
+	}
+
+	// Only the low 5 bits are checked, so bank 0x20 maps 0x21
+	c.SetByte(0x01, 0x4000)
+	if lo, hi := mappedBanks(c); lo != 0 || hi != 0x21 {
+		t.Fatal("Unexpected bank 0x21")
+	}
+
+	// Mode 1 applies BANK2 to 0x0000-0x3FFF
+	c.SetByte(0x01, 0x6000)
+	if lo, _ := mappedBanks(c); lo != 0x20 {
+		t.Fatal("Unexpected bank at 0x0000 in mode 1")
+	}
+
+	// Bank numbers wrap around the ROM size
+	c.SetByte(0x03, 0x4000)
+	c.SetByte(0x12, 0x2000)
+	if lo, hi := mappedBanks(c); lo != 0x60 || hi != 0x72 {
+		t.Fatal("Unexpected banks in a 2 MiB ROM")
+	}
+
+	small := mbc.New(getMBCROM(0x01, 4, 0x00))
+	small.SetByte(0x07, 0x2000)
+	if _, hi := mappedBanks(small); hi != 3 {
+		t.Fatal("Bank number did not wrap around")
+	}
+}
+
+func TestMBC1RAM(t *testing.T) {
+	c := mbc.New(getMBCROM(0x03, 64, 0x03))
+
+	c.SetByte(0x42, 0xA000)
+	if c.GetByte(0xA000) != 0xFF {
+		t.Fatal("RAM not disabled at power-on")
+	}
+
+	c.SetByte(0x0A, 0x0000)
+	c.SetByte(0x42, 0xA000)
+	if c.GetByte(0xA000) != 0x42 {
+		t.Fatal("Unexpected value in RAM")
+	}
+
+	// Mode 0 always maps RAM bank 0
+	c.SetByte(0x02, 0x4000)
+	if c.GetByte(0xA000) != 0x42 {
+		t.Fatal("RAM bank switched in mode 0")
+	}
+
+	c.SetByte(0x01, 0x6000)
+	c.SetByte(0x24, 0xA000)
+	c.SetByte(0x00, 0x4000)
+	if c.GetByte(0xA000) != 0x42 {
+		t.Fatal("Unexpected value in RAM bank 0")
+	}
+
+	c.SetByte(0x00, 0x0000)
+	if c.GetByte(0xA000) != 0xFF {
+		t.Fatal("RAM not disabled")
+	}
+}
+
+func TestMBC1Multicart(t *testing.T) {
+	rom := getMBCROM(0x01, 64, 0x00)
+	for game := range 4 {
+		copy(rom[game*0x10*mbc.ROMBankSize+0x0104:], nintendoLogo)
+	}
+	c := mbc.New(rom).(*mbc.MBC1)
+	if !c.Multicart() {
+		t.Fatal("Multicart not detected")
+	}
+
+	// BANK2 selects the game, and only 4 bits of BANK1 are used
+	c.SetByte(0x02, 0x4000)
+	c.SetByte(0x13, 0x2000)
+	if _, hi := mappedBanks(c); hi != 0x23 {
+		t.Fatal("Unexpected bank")
+	}
+
+	c.SetByte(0x01, 0x6000)
+	if lo, _ := mappedBanks(c); lo != 0x20 {
+		t.Fatal("Unexpected bank at 0x0000 in mode 1")
+	}
+
+	// The bank 0 check still uses all 5 bits
+	c.SetByte(0x10, 0x2000)
+	if _, hi := mappedBanks(c); hi != 0x20 {
+		t.Fatal("Unexpected bank 0x10")
+	}
+
+	if mbc.New(getMBCROM(0x01, 64, 0x00)).(*mbc.MBC1).Multicart() {
+		t.Fatal("Plain MBC1 detected as a multicart")
+	}
+}
+
+func TestMBCBankSwitchCachedBlocks(t *testing.T) {
+	rom := getMBCROM(0x01, 4, 0x00)
+	copy(rom[0x0100:], []byte{0xC3, 0x01, 0x40}) // JP 0x4001
+	copy(rom[0x0150:], []byte{
+		0x3E, 0x02, // LD A, 0x02
+		0xEA, 0x00, 0x20, // LD (0x2000), A
+		0xC3, 0x01, 0x40, // JP 0x4001
+	})
+	copy(rom[1*mbc.ROMBankSize+1:], []byte{
+		0x3E, 0x11, // LD A, 0x11
+		0xC3, 0x50, 0x01, // JP 0x0150
+	})
+	copy(rom[2*mbc.ROMBankSize+1:], []byte{
+		0x06, 0x22, // LD B, 0x22
+		0x76, // HALT
+	})
+
+	// The block cached at 0x4001 must not outlive bank 1
+	for _, engine := range []emulator.Engine{emulator.EngineInterpreter, emulator.EngineCachedBlocks} {
+		emu := getEmulation(t, rom, emulator.Config{Engine: engine})
+		for range 7 {
+			emu.Step()
+		}
+		if emu.CPU.BC>>8 != 0x22 || emu.CPU.AF>>8 != 0x02 {
+			t.Fatal("Code in the new bank not run")
+		}
+	}
+}
+
+var (
+	_ mbc.Battery = &mbc.MBC1{}
+	_ mbc.Battery = &mbc.MBC2{}
+	_ mbc.Battery = &mbc.MBC3{}
+	_ mbc.Battery = &mbc.RTC{}
+)
+
+func TestMBC1SaveData(t *testing.T) {
+	c := mbc.New(getMBCROM(0x03, 4, 0x02)).(*mbc.MBC1)
+	c.SetByte(0x0A, 0x0000)
+	c.SetByte(0x42, 0xA123)
+
+	data := c.SaveData()
+	if len(data) != 0x2000 || data[0x0123] != 0x42 {
+		t.Fatal("Unexpected save data")
+	}
+
+	restored := mbc.New(getMBCROM(0x03, 4, 0x02)).(*mbc.MBC1)
+	if err := restored.LoadSaveData(data); err != nil {
+		t.Fatal(err)
+	}
+	restored.SetByte(0x0A, 0x0000)
+	if restored.GetByte(0xA123) != 0x42 {
+		t.Fatal("Save data not restored")
+	}
+
+	if restored.LoadSaveData(data[:0x1000]) == nil {
+		t.Fatal("Expected an error")
+	}
+}
+
+func TestMBC2ROMBanking(t *testing.T) {
+	c := mbc.New(getMBCROM(0x05, 16, 0x00))
+
+	c.SetByte(0x03, 0x2100)
+	if lo, hi := mappedBanks(c); lo != 0 || hi != 3 {
+		t.Fatal("Unexpected bank 3")
+	}
+
+	// Bit 8 of the address selects the register
+	c.SetByte(0x05, 0x0100)
+	if _, hi := mappedBanks(c); hi != 5 {
+		t.Fatal("Unexpected bank 5")
+	}
+	c.SetByte(0x07, 0x3E00)
+	if _, hi := mappedBanks(c); hi != 5 {
+		t.Fatal("ROM bank set with bit 8 clear")
+	}
+
+	c.SetByte(0x10, 0x2100)
+	if _, hi := mappedBanks(c); hi != 1 {
+		t.Fatal("Bank 0 mapped at 0x4000")
+	}
+
+	c.SetByte(0x06, 0x4100)
+	if _, hi := mappedBanks(c); hi != 1 {
+		t.Fatal("ROM bank set from 0x4000-0x7FFF")
+	}
+}
+
+func TestMBC2RAM(t *testing.T) {
+	c := mbc.New(getMBCROM(0x06, 16, 0x00)).(*mbc.MBC2)
+
+	c.SetByte(0x05, 0xA000)
+	if c.GetByte(0xA000) != 0xFF {
+		t.Fatal("RAM not disabled at power-on")
+	}
+
+	// RAM enable needs bit 8 of the address clear
+	c.SetByte(0x0A, 0x0100)
+	if c.GetByte(0xA000) != 0xFF {
+		t.Fatal("RAM enabled with bit 8 set")
+	}
+	c.SetByte(0x0A, 0x0000)
+
+	c.SetByte(0x35, 0xA001)
+	if c.GetByte(0xA001) != 0xF5 {
+		t.Fatal("Unexpected value in RAM")
+	}
+
+	// The 512 half-bytes are mirrored across 0xA000-0xBFFF
+	if c.GetByte(0xA201) != 0xF5 || c.GetByte(0xBE01) != 0xF5 {
+		t.Fatal("RAM not mirrored")
+	}
+
+	data := c.SaveData()
+	if len(data) != 512 || data[1] != 0x05 {
+		t.Fatal("Unexpected save data")
+	}
+	if !mbc.HasBattery(0x06) || mbc.HasBattery(0x05) {
+		t.Fatal("Unexpected battery")
+	}
+}
+
+func TestMBC2Boots(t *testing.T) {
+	rom := getMBCROM(0x05, 16, 0x00)
+	copy(rom[0x0100:], []byte{
+		0x3E, 0x0A, // LD A, 0x0A
+		0xEA, 0x00, 0x00, // LD (0x0000), A
+		0x3E, 0x07, // LD A, 0x07
+		0xEA, 0x00, 0xA0, // LD (0xA000), A
+		0xFA, 0x00, 0xA0, // LD A, (0xA000)
+	})
+	emu := getEmulation(t, rom, emulator.Config{})
+	for range 5 {
+		emu.Step()
+	}
+	if emu.CPU.AF>>8 != 0xF7 {
+		t.Fatal("Unexpected value read from RAM")
+	}
+}
+
+func TestMBC5ROMBanking(t *testing.T) {
+	c := mbc.New(getMBCROM(0x19, 512, 0x00))
+
+	c.SetByte(0x34, 0x2000)
+	c.SetByte(0x01, 0x3000)
+	if lo, hi := mappedBanks(c); lo != 0 || hi != 0x34 || c.GetByte(0x4001) != 0x01 {
+		t.Fatal("Unexpected bank 0x134")
+	}
+
+	// Bank 0 can be mapped at 0x4000
+	c.SetByte(0x00, 0x2000)
+	c.SetByte(0x00, 0x3000)
+	if _, hi := mappedBanks(c); hi != 0 {
+		t.Fatal("Bank 0 not mapped at 0x4000")
+	}
+}
+
+func TestMBC5RAM(t *testing.T) {
+	c := mbc.New(getMBCROM(0x1B, 4, 0x04))
+
+	// Only 0x0A enables RAM
+	c.SetByte(0x1A, 0x0000)
+	c.SetByte(0x42, 0xA000)
+	if c.GetByte(0xA000) != 0xFF {
+		t.Fatal("RAM enabled by 0x1A")
+	}
+
+	c.SetByte(0x0A, 0x0000)
+	for bank := range 16 {
+		c.SetByte(byte(bank), 0x4000)
+		c.SetByte(byte(0x10+bank), 0xA000)
+	}
+	c.SetByte(0x0F, 0x4000)
+	if c.GetByte(0xA000) != 0x1F {
+		t.Fatal("Unexpected value in RAM bank 15")
+	}
+	if !mbc.HasBattery(0x1B) {
+		t.Fatal("Unexpected battery")
+	}
+}
+
+func TestMBC5Rumble(t *testing.T) {
+	c := mbc.New(getMBCROM(0x1D, 4, 0x03)).(*mbc.MBC5)
+	if !c.Rumble() {
+		t.Fatal("Rumble cartridge not detected")
+	}
+
+	var events []bool
+	c.OnRumble = func(on bool) {
+		events = append(events, on)
+	}
+
+	c.SetByte(0x0A, 0x0000)
+	c.SetByte(0x01, 0x4000)
+	c.SetByte(0x11, 0xA000)
+
+	// Bit 3 drives the motor rather than selecting a bank
+	c.SetByte(0x09, 0x4000)
+	if !c.Motor() || c.GetByte(0xA000) != 0x11 {
+		t.Fatal("Unexpected motor or RAM bank")
+	}
+	c.SetByte(0x0B, 0x4000)
+	c.SetByte(0x03, 0x4000)
+
+	if len(events) != 2 || !events[0] || events[1] {
+		t.Fatal("Unexpected rumble events")
+	}
+
+	plain := mbc.New(getMBCROM(0x1A, 4, 0x04)).(*mbc.MBC5)
+	plain.SetByte(0x08, 0x4000)
+	if plain.Rumble() || plain.Motor() {
+		t.Fatal("Motor on a cartridge without one")
+	}
+}
+
+func TestRumbleHook(t *testing.T) {
+	rom := getMBCROM(0x1C, 4, 0x00)
+	copy(rom[0x0100:], []byte{
+		0x3E, 0x08, // LD A, 0x08
+		0xEA, 0x00, 0x40, // LD (0x4000), A
+		0xAF,             // XOR A
+		0xEA, 0x00, 0x40, // LD (0x4000), A
+	})
+	emu := getEmulation(t, rom, emulator.Config{})
+
+	var events []emulator.RumbleEvent
+	emu.AddHooks(emulator.Hooks{Rumble: func(ev emulator.RumbleEvent) {
+		events = append(events, ev)
+	}})
+	start := emu.Clock.Cycles
+	for range 4 {
+		emu.Step()
+	}
+
+	// Each write happens on the last M-cycle of its instruction
+	if len(events) != 2 || !events[0].On || events[1].On {
+		t.Fatal("Unexpected rumble events")
+	}
+	if events[0].Cycles-start != 6*emulator.TCyclesPerMCycle ||
+		events[1].Cycles-start != 11*emulator.TCyclesPerMCycle {
+		t.Fatal("Unexpected rumble event cycles")
+	}
+}
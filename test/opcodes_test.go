@@ -0,0 +1,181 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/markelmencia/gogb/cpu"
+	"github.com/markelmencia/gogb/cpu/instructions"
+	"github.com/markelmencia/gogb/emulator"
+)
+
+// Flag values that meet each encoded
+// condition (NZ, Z, NC, C).
+var conditionFlags = [4]byte{0x00, 0x80, 0x00, 0x10}
+
+// Flag values that do not meet each
+// encoded condition (NZ, Z, NC, C).
+var conditionNotFlags = [4]byte{0x80, 0x00, 0x10, 0x00}
+
+// Moves the stack pointer of emu away from the edges and
+// points HL to WRAM, so that any opcode can run on it.
+func prepareOpcode(emu *emulator.Emulation) *emulator.Emulation {
+	emu.CPU.SP = 0xC100
+	emu.CPU.HL = 0xC000
+	return emu
+}
+
+func TestOpcodeTableMatchesDecodeTables(t *testing.T) {
+	for op := range 256 {
+		valid := instructions.OpcodeTable[op].Valid()
+		if valid != (emulator.Opcodes[op] != nil || op == 0xCB) {
+			t.Fatalf("Unexpected validity of opcode 0x%02X", op)
+		}
+
+		if !instructions.CBOpcodeTable[op].Valid() {
+			t.Fatalf("Unexpected invalid CB opcode 0x%02X", op)
+		}
+	}
+}
+
+func TestOpcodeTableCycles(t *testing.T) {
+	for op := range 256 {
+		o := instructions.OpcodeTable[op]
+		if !o.Valid() || op == 0xCB || op == 0x10 || op == 0x76 {
+			continue
+		}
+
+		flags := [2]byte{}
+		if o.Conditional() {
+			cc := (op >> 3) & 3
+			flags = [2]byte{conditionFlags[cc], conditionNotFlags[cc]}
+		}
+
+		for i, want := range [2]int{o.Cycles, o.CyclesNotTaken} {
+			emu := prepareOpcode(getEmulation(t, getProgramROM(byte(op)), emulator.Config{}))
+			emu.CPU.SetHalve(cpu.F, flags[i])
+			cycles, err := emu.Step()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cycles != want {
+				t.Fatalf("Unexpected cycle count for %s (0x%02X): %d", o.Mnemonic, op, cycles)
+			}
+		}
+	}
+
+	for op := range 256 {
+		o := instructions.CBOpcodeTable[op]
+		emu := prepareOpcode(getEmulation(t, getProgramROM(0xCB, byte(op)), emulator.Config{}))
+		cycles, err := emu.Step()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cycles != o.Cycles {
+			t.Fatalf("Unexpected cycle count for %s (0xCB 0x%02X): %d", o.Mnemonic, op, cycles)
+		}
+	}
+}
+
+func TestOpcodeTableLengths(t *testing.T) {
+	// Opcodes that write PC
+	jumps := map[int]bool{
+		0x18: true, 0x20: true, 0x28: true, 0x30: true, 0x38: true,
+		0xC0: true, 0xC2: true, 0xC3: true, 0xC4: true, 0xC7: true, 0xC8: true, 0xC9: true,
+		0xCA: true, 0xCC: true, 0xCD: true, 0xCF: true, 0xD0: true, 0xD2: true, 0xD4: true,
+		0xD7: true, 0xD8: true, 0xD9: true, 0xDA: true, 0xDC: true, 0xDF: true, 0xE7: true,
+		0xE9: true, 0xEF: true, 0xF7: true, 0xFF: true,
+	}
+
+	for op := range 256 {
+		o := instructions.OpcodeTable[op]
+		if !o.Valid() || op == 0xCB || jumps[op] {
+			continue
+		}
+
+		emu := prepareOpcode(getEmulation(t, getProgramROM(byte(op)), emulator.Config{}))
+		emu.Step()
+		if int(emu.CPU.PC)-0x100 != o.Length {
+			t.Fatalf("Unexpected length for %s (0x%02X)", o.Mnemonic, op)
+		}
+	}
+}
+
+func TestOpcodeTableFlags(t *testing.T) {
+	o := instructions.OpcodeTable[0x3C] // INC A
+	if o.Flags[cpu.FlagZ] != instructions.FlagComputed || o.Flags[cpu.FlagN] != instructions.FlagReset ||
+		o.Flags[cpu.FlagH] != instructions.FlagComputed || o.Flags[cpu.FlagC] != instructions.FlagPreserved {
+		t.Fatal("Unexpected flag effects")
+	}
+
+	o = instructions.OpcodeTable[0x37] // SCF
+	if o.Flags[cpu.FlagC] != instructions.FlagSet {
+		t.Fatal("Unexpected flag effects")
+	}
+}
+
+// Steps the emulation once from each of the given flag
+// values, failing the test if a flag that o preserves,
+// resets or sets ends with any other value.
+func checkFlagEffects(t *testing.T, o instructions.Opcode, program ...byte) {
+	for _, f := range []byte{0x00, 0xF0} {
+		emu := prepareOpcode(getEmulation(t, getProgramROM(program...), emulator.Config{}))
+		emu.CPU.SetHalve(cpu.F, f)
+		if _, err := emu.Step(); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, flag := range []cpu.Flag{cpu.FlagZ, cpu.FlagN, cpu.FlagH, cpu.FlagC} {
+			set := emu.CPU.IsFlag(flag)
+			switch o.Flags[flag] {
+			case instructions.FlagPreserved:
+				if set != (f != 0x00) {
+					t.Fatalf("Unexpected flag %d after %s (% X)", flag, o.Mnemonic, program)
+				}
+			case instructions.FlagReset:
+				if set {
+					t.Fatalf("Unexpected flag %d after %s (% X)", flag, o.Mnemonic, program)
+				}
+			case instructions.FlagSet:
+				if !set {
+					t.Fatalf("Unexpected flag %d after %s (% X)", flag, o.Mnemonic, program)
+				}
+			}
+		}
+	}
+}
+
+func TestOpcodeTableFlagsMatchHandlers(t *testing.T) {
+	for op := range 256 {
+		o := instructions.OpcodeTable[op]
+		if !o.Valid() || op == 0xCB {
+			continue
+		}
+		checkFlagEffects(t, o, byte(op))
+	}
+
+	for op := range 256 {
+		checkFlagEffects(t, instructions.CBOpcodeTable[op], 0xCB, byte(op))
+	}
+}
+
+func TestDisassemble(t *testing.T) {
+	for _, c := range []struct {
+		code        []byte
+		instruction string
+		length      int
+	}{
+		{[]byte{0x00}, "NOP", 1},
+		{[]byte{0xC3, 0x50, 0x01}, "JP $0150", 3},
+		{[]byte{0x3E, 0x12}, "LD A, $12", 2},
+		{[]byte{0x18, 0xFE}, "JR -2", 2},
+		{[]byte{0xF8, 0x05}, "LD HL, SP+5", 2},
+		{[]byte{0xCB, 0x7C}, "BIT 7, H", 2},
+		{[]byte{0xD3}, "DB $D3", 1},
+		{[]byte{0xC3, 0x50}, "DB $C3", 1},
+	} {
+		instruction, length := instructions.Disassemble(c.code)
+		if instruction != c.instruction || length != c.length {
+			t.Fatalf("Unexpected disassembly: %s (%d bytes)", instruction, length)
+		}
+	}
+}
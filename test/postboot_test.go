@@ -0,0 +1,118 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/markelmencia/gogb/emulator"
+	"github.com/markelmencia/gogb/model"
+)
+
+// Returns a ROM with the given title, CGB flag,
+// old licensee code and header checksum.
+func getHeaderROM(title string, cgbFlag, licensee, checksum byte) []byte {
+	rom := make([]byte, 0x8000)
+	copy(rom[0x0134:0x0144], title)
+	rom[0x0143] = cgbFlag
+	rom[0x014B] = licensee
+	rom[0x014D] = checksum
+	return rom
+}
+
+func TestPostBootDMG(t *testing.T) {
+	emu := getEmulation(t, getHeaderROM("GAME", 0x00, 0x00, 0x12), emulator.Config{})
+
+	if emu.CPU.AF != 0x01B0 || emu.CPU.BC != 0x0013 ||
+		emu.CPU.DE != 0x00D8 || emu.CPU.HL != 0x014D {
+		t.Fatal("Unexpected register values")
+	}
+
+	if emu.CPU.SP != 0xFFFE || emu.CPU.PC != 0x0100 {
+		t.Fatal("Unexpected SP or PC value")
+	}
+
+	if emu.RAM.GetByte(0xFF40) != 0x91 || emu.RAM.GetByte(0xFF04) != 0xAB ||
+		emu.RAM.GetByte(0xFF26) != 0xF1 {
+		t.Fatal("Unexpected I/O register values")
+	}
+
+	if emu.CGBMode {
+		t.Fatal("DMG in CGB mode")
+	}
+}
+
+func TestPostBootDMGChecksumFlags(t *testing.T) {
+	emu := getEmulation(t, getHeaderROM("GAME", 0x00, 0x00, 0x00), emulator.Config{})
+
+	if emu.CPU.AF != 0x0180 {
+		t.Fatal("Unexpected AF value")
+	}
+}
+
+func TestPostBootModels(t *testing.T) {
+	rom := getHeaderROM("GAME", 0x00, 0x00, 0x12)
+	for _, c := range []struct {
+		model          model.Model
+		af, bc, de, hl uint16
+	}{
+		{model.DMG0, 0x0100, 0xFF13, 0x00C1, 0x8403},
+		{model.MGB, 0xFFB0, 0x0013, 0x00D8, 0x014D},
+		{model.SGB, 0x0100, 0x0014, 0x0000, 0xC060},
+		{model.SGB2, 0xFF00, 0x0014, 0x0000, 0xC060},
+		{model.CGB, 0x1180, 0x0000, 0x0008, 0x007C},
+		{model.AGB, 0x1100, 0x0100, 0x0008, 0x007C},
+	} {
+		emu := getEmulation(t, rom, emulator.Config{Model: c.model})
+		if emu.CPU.AF != c.af || emu.CPU.BC != c.bc ||
+			emu.CPU.DE != c.de || emu.CPU.HL != c.hl {
+			t.Fatalf("Unexpected register values in %s", c.model)
+		}
+	}
+}
+
+func TestPostBootDMG0IORegisters(t *testing.T) {
+	emu := getEmulation(t, getHeaderROM("GAME", 0x00, 0x00, 0x12), emulator.Config{Model: model.DMG0})
+
+	if emu.RAM.GetByte(0xFF04) != 0x18 || emu.RAM.GetByte(0xFF41) != 0x81 {
+		t.Fatal("Unexpected I/O register values")
+	}
+}
+
+func TestPostBootCGBMode(t *testing.T) {
+	rom := getHeaderROM("GAME", 0x80, 0x00, 0x12)
+
+	emu := getEmulation(t, rom, emulator.Config{Model: model.CGB})
+	if emu.CPU.AF != 0x1180 || emu.CPU.BC != 0x0000 ||
+		emu.CPU.DE != 0xFF56 || emu.CPU.HL != 0x000D {
+		t.Fatal("Unexpected register values in CGB")
+	}
+
+	if !emu.CGBMode {
+		t.Fatal("CGB cartridge not in CGB mode")
+	}
+
+	if emu.RAM.GetByte(0xFF70) != 0xF8 || emu.RAM.GetByte(0xFF4D) != 0x7E {
+		t.Fatal("Unexpected I/O register values")
+	}
+
+	emu = getEmulation(t, rom, emulator.Config{Model: model.AGB})
+	if emu.CPU.AF != 0x1100 || emu.CPU.BC != 0x0100 {
+		t.Fatal("Unexpected register values in AGB")
+	}
+}
+
+func TestPostBootCGBTitleChecksum(t *testing.T) {
+	// Nintendo cartridges get B from the sum of the title
+	rom := getHeaderROM("\x20\x20\x03", 0x00, 0x01, 0x12) // Sum: 0x43
+
+	emu := getEmulation(t, rom, emulator.Config{Model: model.CGB})
+	if emu.CPU.BC != 0x4300 || emu.CPU.HL != 0x991A {
+		t.Fatal("Unexpected register values in CGB")
+	}
+
+	// The AGB increments B, setting Z and H accordingly
+	rom = getHeaderROM("\xF0\x0F", 0x00, 0x01, 0x12) // Sum: 0xFF
+	emu = getEmulation(t, rom, emulator.Config{Model: model.AGB})
+	if emu.CPU.BC != 0x0000 || emu.CPU.AF != 0x11A0 {
+		t.Fatal("Unexpected register values in AGB")
+	}
+}
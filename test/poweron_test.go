@@ -0,0 +1,76 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/markelmencia/gogb/emulator"
+	"github.com/markelmencia/gogb/model"
+)
+
+// VRAM, WRAM and HRAM, as ranges for sameMemory.
+var ramRanges = [][2]int{{0x8000, 0xA000}, {0xC000, 0xE000}, {0xFF80, 0x10000}}
+
+func TestRandomizeRAM(t *testing.T) {
+	rom := getHeaderROM("GAME", 0x80, 0x00, 0x12)
+	for _, md := range []model.Model{model.DMG, model.CGB} {
+		a := getEmulation(t, rom, emulator.Config{Model: md, RandomizeRAM: true, Seed: 42})
+		b := getEmulation(t, rom, emulator.Config{Model: md, RandomizeRAM: true, Seed: 42})
+		c := getEmulation(t, rom, emulator.Config{Model: md, RandomizeRAM: true, Seed: 43})
+
+		if a.Seed != 42 {
+			t.Fatal("Unexpected seed")
+		}
+		if !sameMemory(a.RAM, b.RAM, ramRanges...) {
+			t.Fatalf("Same seed generated different RAM on %s", md)
+		}
+		if sameMemory(a.RAM, c.RAM, ramRanges...) {
+			t.Fatalf("Different seeds generated the same RAM on %s", md)
+		}
+		if a.RAM.GetByte(0xFFFF) != 0x00 {
+			t.Fatal("IE was randomized")
+		}
+	}
+}
+
+func TestRandomizeRAMSeedRecorded(t *testing.T) {
+	rom := getHeaderROM("GAME", 0x00, 0x00, 0x12)
+	a := getEmulation(t, rom, emulator.Config{RandomizeRAM: true})
+	if a.Seed == 0 {
+		t.Fatal("Seed not recorded")
+	}
+
+	// The recorded seed reproduces the run
+	b := getEmulation(t, rom, emulator.Config{RandomizeRAM: true, Seed: a.Seed})
+	if !sameMemory(a.RAM, b.RAM, ramRanges...) {
+		t.Fatal("Recorded seed did not reproduce RAM")
+	}
+}
+
+func TestRandomizeRAMCGBBanks(t *testing.T) {
+	emu := getEmulation(t, getHeaderROM("GAME", 0x80, 0x00, 0x12),
+		emulator.Config{Model: model.CGB, RandomizeRAM: true, Seed: 1})
+
+	// Runs of 0x00 and 0xFF with a few bits flipped
+	zeros := 0
+	for a := uint16(0xC000); a < 0xC008; a++ {
+		if emu.RAM.GetByte(a) == 0x00 {
+			zeros++
+		}
+	}
+	if zeros < 2 {
+		t.Fatal("Unexpected WRAM pattern")
+	}
+
+	// Switchable banks are filled too
+	emu.Write(0x07, 0xFF70)
+	if emu.Read(0xD000) == 0x00 && emu.Read(0xD008) == 0x00 && emu.Read(0xD009) == 0x00 {
+		t.Fatal("WRAM bank 7 not randomized")
+	}
+}
+
+func TestZeroedRAM(t *testing.T) {
+	emu := getEmulation(t, getProgramROM(), emulator.Config{})
+	if emu.Seed != 0 || emu.RAM.GetByte(0xC000) != 0x00 || emu.RAM.GetByte(0xFF80) != 0x00 {
+		t.Fatal("RAM not zeroed")
+	}
+}
@@ -0,0 +1,248 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/markelmencia/gogb/mbc"
+)
+
+// Clock source that only moves when told to.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+// Returns an MBC3 with RAM and a clock counting from
+// a fake clock source, with RAM and the RTC enabled.
+func getRTCCartridge() (*mbc.MBC3, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2001, 11, 21, 0, 0, 0, 0, time.UTC)}
+	c := mbc.New(getMBCROM(0x10, 8, 0x03)).(*mbc.MBC3)
+	c.RTC.Now = clock.Now
+	c.SetByte(0x0A, 0x0000)
+	return c, clock
+}
+
+// Returns the latched value of RTC register reg.
+func readRTC(c *mbc.MBC3, reg byte) byte {
+	c.SetByte(reg, 0x4000)
+	return c.GetByte(0xA000)
+}
+
+// Sets v into RTC register reg.
+func writeRTC(c *mbc.MBC3, reg, v byte) {
+	c.SetByte(reg, 0x4000)
+	c.SetByte(v, 0xA000)
+}
+
+// Latches the clock with the 0x00, 0x01 sequence.
+func latchRTC(c *mbc.MBC3) {
+	c.SetByte(0x00, 0x6000)
+	c.SetByte(0x01, 0x6000)
+}
+
+func TestMBC3Banking(t *testing.T) {
+	c := mbc.New(getMBCROM(0x13, 128, 0x03))
+
+	c.SetByte(0x7F, 0x2000)
+	if lo, hi := mappedBanks(c); lo != 0 || hi != 0x7F {
+		t.Fatal("Unexpected bank 0x7F")
+	}
+	c.SetByte(0x00, 0x2000)
+	if _, hi := mappedBanks(c); hi != 1 {
+		t.Fatal("Bank 0 mapped at 0x4000")
+	}
+
+	c.SetByte(0x0A, 0x0000)
+	c.SetByte(0x02, 0x4000)
+	c.SetByte(0x22, 0xA000)
+	c.SetByte(0x03, 0x4000)
+	c.SetByte(0x33, 0xA000)
+	c.SetByte(0x02, 0x4000)
+	if c.GetByte(0xA000) != 0x22 {
+		t.Fatal("Unexpected value in RAM bank 2")
+	}
+
+	// No clock to select
+	c.SetByte(0x08, 0x4000)
+	if c.GetByte(0xA000) != 0xFF {
+		t.Fatal("Unexpected RTC register without a clock")
+	}
+}
+
+func TestRTCCounting(t *testing.T) {
+	c, clock := getRTCCartridge()
+	latchRTC(c) // Starts the clock
+
+	clock.advance(26*time.Hour + 2*time.Minute + 3*time.Second + 500*time.Millisecond)
+	if readRTC(c, mbc.RTCSeconds) != 0x00 {
+		t.Fatal("Registers changed without latching")
+	}
+
+	latchRTC(c)
+	if readRTC(c, mbc.RTCSeconds) != 3 || readRTC(c, mbc.RTCMinutes) != 2 ||
+		readRTC(c, mbc.RTCHours) != 2 || readRTC(c, mbc.RTCDaysLow) != 1 {
+		t.Fatal("Unexpected time")
+	}
+
+	// The half second left is not lost
+	clock.advance(500 * time.Millisecond)
+	latchRTC(c)
+	if readRTC(c, mbc.RTCSeconds) != 4 {
+		t.Fatal("Unexpected seconds")
+	}
+}
+
+func TestRTCLatchSequence(t *testing.T) {
+	c, clock := getRTCCartridge()
+	latchRTC(c)
+
+	clock.advance(10 * time.Second)
+	c.SetByte(0x01, 0x6000)
+	c.SetByte(0x01, 0x6000)
+	if readRTC(c, mbc.RTCSeconds) != 0 {
+		t.Fatal("Latched without writing 0x00 first")
+	}
+
+	c.SetByte(0x00, 0x6000)
+	c.SetByte(0x01, 0x6000)
+	if readRTC(c, mbc.RTCSeconds) != 10 {
+		t.Fatal("Not latched")
+	}
+
+	c.SetByte(0x00, 0x0000)
+	if readRTC(c, mbc.RTCSeconds) != 0xFF {
+		t.Fatal("RTC not disabled")
+	}
+}
+
+func TestRTCHalt(t *testing.T) {
+	c, clock := getRTCCartridge()
+	writeRTC(c, mbc.RTCDaysHigh, 0x40)
+
+	clock.advance(time.Minute)
+	latchRTC(c)
+	if readRTC(c, mbc.RTCSeconds) != 0 || readRTC(c, mbc.RTCDaysHigh) != 0x40 {
+		t.Fatal("Halted clock counted")
+	}
+
+	writeRTC(c, mbc.RTCDaysHigh, 0x00)
+	clock.advance(5 * time.Second)
+	latchRTC(c)
+	if readRTC(c, mbc.RTCSeconds) != 5 {
+		t.Fatal("Unexpected seconds after resuming")
+	}
+}
+
+func TestRTCDayCarry(t *testing.T) {
+	c, clock := getRTCCartridge()
+	writeRTC(c, mbc.RTCSeconds, 59)
+	writeRTC(c, mbc.RTCMinutes, 59)
+	writeRTC(c, mbc.RTCHours, 23)
+	writeRTC(c, mbc.RTCDaysLow, 0xFF)
+	writeRTC(c, mbc.RTCDaysHigh, 0x01)
+
+	clock.advance(time.Second)
+	latchRTC(c)
+	if readRTC(c, mbc.RTCHours) != 0 || readRTC(c, mbc.RTCDaysLow) != 0 ||
+		readRTC(c, mbc.RTCDaysHigh) != 0x80 {
+		t.Fatal("Day counter did not overflow")
+	}
+
+	// The carry stays set until it is written
+	clock.advance(48 * time.Hour)
+	latchRTC(c)
+	if readRTC(c, mbc.RTCDaysLow) != 2 || readRTC(c, mbc.RTCDaysHigh) != 0x80 {
+		t.Fatal("Carry cleared")
+	}
+	writeRTC(c, mbc.RTCDaysHigh, 0x00)
+	latchRTC(c)
+	if readRTC(c, mbc.RTCDaysHigh) != 0x00 {
+		t.Fatal("Carry not cleared")
+	}
+}
+
+func TestRTCInvalidValues(t *testing.T) {
+	c, clock := getRTCCartridge()
+	writeRTC(c, mbc.RTCSeconds, 0xFE)
+	latchRTC(c)
+	if readRTC(c, mbc.RTCSeconds) != 0x3E {
+		t.Fatal("Unexpected seconds")
+	}
+
+	// Out of range values wrap around without carrying
+	clock.advance(2 * time.Second)
+	latchRTC(c)
+	if readRTC(c, mbc.RTCSeconds) != 0 || readRTC(c, mbc.RTCMinutes) != 0 {
+		t.Fatal("Unexpected time after wrapping around")
+	}
+
+	clock.advance(61 * time.Second)
+	latchRTC(c)
+	if readRTC(c, mbc.RTCSeconds) != 1 || readRTC(c, mbc.RTCMinutes) != 1 {
+		t.Fatal("Unexpected time")
+	}
+}
+
+func TestRTCSecondsWrite(t *testing.T) {
+	c, clock := getRTCCartridge()
+	latchRTC(c)
+
+	// Writing the seconds restarts the current second
+	clock.advance(600 * time.Millisecond)
+	writeRTC(c, mbc.RTCSeconds, 0)
+	clock.advance(600 * time.Millisecond)
+	latchRTC(c)
+	if readRTC(c, mbc.RTCSeconds) != 0 {
+		t.Fatal("Second not restarted")
+	}
+}
+
+func TestRTCSaveData(t *testing.T) {
+	c, clock := getRTCCartridge()
+	c.SetByte(0x00, 0x4000)
+	c.SetByte(0x42, 0xA000)
+	writeRTC(c, mbc.RTCHours, 5)
+	latchRTC(c)
+
+	data := c.SaveData()
+	if len(data) != 0x8000+mbc.RTCSaveDataSize {
+		t.Fatal("Unexpected save data length")
+	}
+
+	// The time the cartridge spends unplugged is counted
+	restored, _ := getRTCCartridge()
+	clock.advance(24 * time.Hour)
+	restored.RTC.Now = clock.Now
+	if err := restored.LoadSaveData(data); err != nil {
+		t.Fatal(err)
+	}
+
+	if readRTC(restored, mbc.RTCHours) != 5 || readRTC(restored, mbc.RTCDaysLow) != 0 {
+		t.Fatal("Latched registers not restored")
+	}
+	latchRTC(restored)
+	if readRTC(restored, mbc.RTCHours) != 5 || readRTC(restored, mbc.RTCDaysLow) != 1 {
+		t.Fatal("Unexpected time after restoring")
+	}
+
+	restored.SetByte(0x00, 0x4000)
+	if restored.GetByte(0xA000) != 0x42 {
+		t.Fatal("RAM not restored")
+	}
+
+	// Save data without the clock state
+	if err := restored.LoadSaveData(data[:0x8000]); err != nil {
+		t.Fatal(err)
+	}
+	if restored.LoadSaveData(data[:0x8000+10]) == nil {
+		t.Fatal("Expected an error")
+	}
+}
@@ -0,0 +1,85 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/markelmencia/gogb/emulator"
+)
+
+// Program that loops forever incrementing A.
+var loopProgram = []byte{
+	0x3C,             // INC A
+	0xC3, 0x00, 0x01, // JP 0x0100
+}
+
+func TestRunFor(t *testing.T) {
+	emu := getEmulation(t, getProgramROM(loopProgram...), emulator.Config{})
+
+	reason, err := emu.RunFor(2 * emulator.MCyclesPerSecond)
+	if reason != emulator.StopBudget || err != nil {
+		t.Fatal("Unexpected stop reason")
+	}
+
+	// Each iteration takes 5 M-cycles, so the
+	// budget may be overshot by up to 4
+	cycles := emu.Clock.Cycles / emulator.TCyclesPerMCycle
+	if cycles < 2*emulator.MCyclesPerSecond || cycles >= 2*emulator.MCyclesPerSecond+5 {
+		t.Fatal("Unexpected cycle count")
+	}
+}
+
+func TestRunFrames(t *testing.T) {
+	emu := getEmulation(t, getProgramROM(loopProgram...), emulator.Config{})
+	emu.Clock.DoubleSpeed = true
+
+	emu.RunFrames(2)
+
+	// Double speed runs twice as many M-cycles per frame
+	cycles := emu.Clock.Cycles / emulator.TCyclesPerMCycle
+	if emu.Clock.Dots < 2*emulator.DotsPerFrame || cycles < emulator.DotsPerFrame {
+		t.Fatal("Unexpected cycle count")
+	}
+}
+
+func TestRunUntil(t *testing.T) {
+	emu := getEmulation(t, getProgramROM(loopProgram...), emulator.Config{})
+
+	reason, err := emu.RunUntil(func(e *emulator.Emulation) bool {
+		return e.CPU.AF>>8 == 0x10
+	})
+	if reason != emulator.StopBreakpoint || err != nil {
+		t.Fatal("Unexpected stop reason")
+	}
+
+	if emu.CPU.PC != 0x0101 {
+		t.Fatal("Unexpected PC value")
+	}
+}
+
+func TestRunFault(t *testing.T) {
+	emu := getEmulation(t, getProgramROM(0xD3), emulator.Config{}) // Illegal opcode
+	emu.FaultPolicy = emulator.FaultStop
+
+	reason, err := emu.RunFor(100)
+	if reason != emulator.StopFault || err == nil {
+		t.Fatal("Unexpected stop reason")
+	}
+}
+
+func TestRunCancel(t *testing.T) {
+	emu := getEmulation(t, getProgramROM(loopProgram...), emulator.Config{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+
+	reason, err := emu.Run(ctx)
+	if reason != emulator.StopCancelled || !errors.Is(err, context.Canceled) {
+		t.Fatal("Unexpected stop reason")
+	}
+}
@@ -0,0 +1,118 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/markelmencia/gogb/cpu"
+	"github.com/markelmencia/gogb/emulator"
+	"github.com/markelmencia/gogb/model"
+)
+
+// Returns a CGB cartridge with the given
+// program at the entry point (0x0100).
+func getCGBProgramROM(program ...byte) []byte {
+	rom := getHeaderROM("GAME", 0x80, 0x00, 0x12)
+	copy(rom[0x0100:], program)
+	return rom
+}
+
+func TestSpeedSwitch(t *testing.T) {
+	emu := getEmulation(t, getCGBProgramROM(
+		0x3E, 0x01, // LD A, 0x01
+		0xE0, 0x4D, // LDH (0x4D), A
+		0x10, 0x00, // STOP
+		0x3E, 0x42, // LD A, 0x42
+	), emulator.Config{Model: model.CGB})
+
+	if emu.RAM.GetByte(0xFF4D) != 0x7E {
+		t.Fatal("Unexpected KEY1 value")
+	}
+
+	emu.Step()
+	emu.Step()
+	if emu.RAM.GetByte(0xFF4D) != 0x7F {
+		t.Fatal("Speed switch not armed")
+	}
+
+	emu.Step()
+	if emu.CPU.Stopped || !emu.Clock.DoubleSpeed || emu.RAM.GetByte(0xFF4D) != 0xFE {
+		t.Fatal("Speed did not switch")
+	}
+
+	// The CPU is paused during the switch
+	for range 2050 {
+		emu.Step()
+	}
+	if emu.CPU.GetHalve(cpu.A) != 0x01 {
+		t.Fatal("CPU resumed during the speed switch")
+	}
+
+	emu.Step()
+	if emu.CPU.GetHalve(cpu.A) != 0x42 {
+		t.Fatal("CPU did not resume after the speed switch")
+	}
+}
+
+func TestSpeedSwitchKEY1ReadOnlyBits(t *testing.T) {
+	emu := getEmulation(t, getCGBProgramROM(), emulator.Config{Model: model.CGB})
+
+	emu.Write(0x80, 0xFF4D)
+	if emu.RAM.GetByte(0xFF4D) != 0x7E {
+		t.Fatal("Unexpected KEY1 value")
+	}
+}
+
+func TestSpeedSwitchDMG(t *testing.T) {
+	emu := getEmulation(t, getProgramROM(0x10, 0x00), emulator.Config{}) // STOP
+	emu.RAM.SetByte(0x01, 0xFF4D)
+
+	emu.Step()
+	if !emu.CPU.Stopped || emu.Clock.DoubleSpeed {
+		t.Fatal("DMG switched speed")
+	}
+}
+
+func TestDoubleSpeedClockDomains(t *testing.T) {
+	emu := getEmulation(t, getCGBProgramROM(), emulator.Config{Model: model.CGB})
+	emu.Clock.DoubleSpeed = true
+
+	cpuTicks, dotTicks := 0, 0
+	emu.Clock.Attach(&probe{onTick: func(int) { cpuTicks++ }})
+	emu.Clock.AttachDots(&probe{onTick: func(int) { dotTicks++ }})
+
+	emu.Clock.Advance(8)
+	if cpuTicks != 8 || dotTicks != 4 || emu.Clock.Dots != 16 {
+		t.Fatal("Unexpected ticks in double speed mode")
+	}
+
+	emu.Clock.DoubleSpeed = false
+	emu.Clock.Advance(8)
+	if cpuTicks != 16 || dotTicks != 12 {
+		t.Fatal("Unexpected ticks in normal speed mode")
+	}
+}
+
+func TestRunForDoubleSpeed(t *testing.T) {
+	emu := getEmulation(t, getCGBProgramROM(
+		0x3E, 0x01, // 0x0100: LD A, 0x01
+		0xE0, 0x4D, // 0x0102: LDH (0x4D), A
+		0x10, 0x00, // 0x0104: STOP
+		0x3C,             // 0x0106: INC A
+		0xC3, 0x06, 0x01, // 0x0107: JP 0x0106
+	), emulator.Config{Model: model.CGB})
+	emu.RunUntil(func(e *emulator.Emulation) bool {
+		return e.Clock.DoubleSpeed && e.CPU.SpeedSwitch == 0
+	})
+
+	// The budget is in M-cycles at the current speed,
+	// which take half the time in double speed mode
+	cycles, dots := emu.Clock.Cycles, emu.Clock.Dots
+	emu.RunFor(1000)
+	m := (emu.Clock.Cycles - cycles) / emulator.TCyclesPerMCycle
+	if m < 1000 || m >= 1005 {
+		t.Fatal("Unexpected M-cycle count")
+	}
+	if emu.Clock.Dots-dots != m*emulator.TCyclesPerMCycle/2 {
+		t.Fatal("Unexpected dot count")
+	}
+}